@@ -0,0 +1,334 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// AssertEqual panics if got is not equal to want. The value passed to
+// `panic()` is an [*AssertionError] whose message includes both values,
+// e.g., `expected equal, got 3 and 5`.
+//
+// Use this function instead of `Assert(got == want)` when knowing the
+// offending values would help to understand why the invariant broke.
+func AssertEqual[T comparable](got, want T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if got != want {
+		assertionFailed(fmt.Errorf("expected equal, got %v and %v", got, want))
+	}
+}
+
+// maxShownValueRunes is the maximum number of runes of the rendering
+// of each value that [AssertDeepEqual] includes in its message.
+const maxShownValueRunes = 200
+
+// AssertDeepEqual panics unless [reflect.DeepEqual] reports that got and want
+// are deeply equal. The value passed to `panic()` is an [*AssertionError] whose
+// message includes the Go-syntax representation of both values, truncated when
+// longer than 200 runes, e.g., `expected deep equal, got []int{1, 2} and
+// []int{1, 3}`.
+//
+// Use this function for values that are not comparable, e.g., slices of
+// structs or nested maps. Since it uses reflection, it is much slower than
+// [AssertEqual], which you should prefer for comparable types.
+func AssertDeepEqual(got, want any) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		assertionFailed(fmt.Errorf("expected deep equal, got %s and %s",
+			truncateHead(fmt.Sprintf("%#v", got), maxShownValueRunes),
+			truncateHead(fmt.Sprintf("%#v", want), maxShownValueRunes)))
+	}
+}
+
+// AssertNotEqual panics if a is equal to b. The value passed to
+// `panic()` is an [*AssertionError] whose message includes both values,
+// e.g., `expected not equal, got 5 and 5`.
+func AssertNotEqual[T comparable](a, b T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if a == b {
+		assertionFailed(fmt.Errorf("expected not equal, got %v and %v", a, b))
+	}
+}
+
+// AssertZero panics unless v is the zero value of its type. The value passed
+// to `panic()` is an [*AssertionError] whose message includes v, e.g.,
+// `expected zero value, got 17`.
+func AssertZero[T comparable](v T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	var zero T
+	if v != zero {
+		assertionFailed(fmt.Errorf("expected zero value, got %v", v))
+	}
+}
+
+// AssertNotZero panics if v is the zero value of its type. The value passed
+// to `panic()` is an [*AssertionError] whose message includes v, e.g.,
+// `expected non-zero value, got 0`.
+func AssertNotZero[T comparable](v T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	var zero T
+	if v == zero {
+		assertionFailed(fmt.Errorf("expected non-zero value, got %v", v))
+	}
+}
+
+// AssertNil panics if v is not nil. The value passed to `panic()` is an
+// [*AssertionError] whose message includes the type of v, e.g., `expected
+// nil, got *bytes.Buffer`.
+//
+// Unlike `Assert(v == nil)`, this function also treats as nil a nil pointer,
+// map, slice, channel, or func wrapped inside a non-nil interface.
+func AssertNil(v any) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !isNil(v) {
+		assertionFailed(fmt.Errorf("expected nil, got %T", v))
+	}
+}
+
+// AssertNotNil panics if v is nil. The value passed to `panic()` is an
+// [*AssertionError] whose message is `expected non-nil value`.
+//
+// Unlike `Assert(v != nil)`, this function also panics when v is a nil
+// pointer, map, slice, channel, or func wrapped inside a non-nil interface.
+func AssertNotNil(v any) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if isNil(v) {
+		assertionFailed(errors.New("expected non-nil value"))
+	}
+}
+
+// isNil returns whether v is nil or wraps a nil value.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// AssertLen panics if the length of collection is not want. The value passed
+// to `panic()` is an [*AssertionError] whose message includes both lengths,
+// e.g., `expected length 3, got 5`. A nil slice has length zero.
+func AssertLen[T any](collection []T, want int) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if got := len(collection); got != want {
+		assertionFailed(fmt.Errorf("expected length %d, got %d", want, got))
+	}
+}
+
+// AssertCapAtLeast panics if the capacity of s is less than minCap. The value
+// passed to `panic()` is an [*AssertionError] whose message includes both
+// capacities, e.g., `expected capacity >= 64, got 32`. A nil slice has
+// capacity zero. Use it to check that a preallocated buffer has not been
+// reallocated, e.g., when returning it to a pool.
+func AssertCapAtLeast[T any](s []T, minCap int) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if got := cap(s); got < minCap {
+		assertionFailed(fmt.Errorf("expected capacity >= %d, got %d", minCap, got))
+	}
+}
+
+// AssertLenAny is like [AssertLen] but uses reflection to support arrays,
+// channels, maps, slices, strings, and pointers to arrays. It panics with
+// an [*AssertionError] whose message is `type X has no length` if v does
+// not have a length (including the case where v is nil).
+func AssertLenAny(v any, want int) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Chan, reflect.Map, reflect.Slice, reflect.String:
+		// nothing
+	case reflect.Pointer:
+		if rv.Type().Elem().Kind() != reflect.Array {
+			assertionFailed(fmt.Errorf("type %T has no length", v))
+			return
+		}
+	default:
+		assertionFailed(fmt.Errorf("type %T has no length", v))
+		return
+	}
+	if got := rv.Len(); got != want {
+		assertionFailed(fmt.Errorf("expected length %d, got %d", want, got))
+	}
+}
+
+// AssertNotEmptyString panics if s is empty. The value passed to `panic()`
+// is an [*AssertionError] whose message is `expected non-empty string`.
+func AssertNotEmptyString(s string) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if s == "" {
+		assertionFailed(errors.New("expected non-empty string"))
+	}
+}
+
+// AssertNotEmptySlice panics if s is empty. The value passed to `panic()` is
+// an [*AssertionError] whose message is `expected non-empty slice`. A nil slice
+// is empty.
+func AssertNotEmptySlice[T any](s []T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if len(s) <= 0 {
+		assertionFailed(errors.New("expected non-empty slice"))
+	}
+}
+
+// AssertNotEmptyMap panics if m is empty. The value passed to `panic()` is
+// an [*AssertionError] whose message is `expected non-empty map`. A nil map
+// is empty.
+func AssertNotEmptyMap[K comparable, V any](m map[K]V) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if len(m) <= 0 {
+		assertionFailed(errors.New("expected non-empty map"))
+	}
+}
+
+// AssertSliceContains panics if needle is not in haystack. The value passed
+// to `panic()` is an [*AssertionError] whose message includes the needle,
+// e.g., `value 4 not found in slice`.
+func AssertSliceContains[T comparable](haystack []T, needle T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !slices.Contains(haystack, needle) {
+		assertionFailed(fmt.Errorf("value %v not found in slice", needle))
+	}
+}
+
+// AssertMapHasKey panics if key is not in m. The value passed to `panic()`
+// is an [*AssertionError] whose message includes the key, e.g., `key foo
+// not present in map`.
+func AssertMapHasKey[K comparable, V any](m map[K]V, key K) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if _, found := m[key]; !found {
+		assertionFailed(fmt.Errorf("key %v not present in map", key))
+	}
+}
+
+// AssertErrorIs panics unless [errors.Is] reports that err matches target. The
+// value passed to `panic()` is an [*AssertionError] whose message includes
+// both errors, e.g., `expected error matching EOF, got unexpected EOF`.
+func AssertErrorIs(err, target error) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !errors.Is(err, target) {
+		assertionFailed(fmt.Errorf("expected error matching %v, got %v", target, err))
+	}
+}
+
+// AssertErrorAs panics unless [errors.As] finds an error of type T in the
+// chain of err, in which case it returns the error it found. The value
+// passed to `panic()` is an [*AssertionError] whose message includes the
+// expected type and the error, e.g., `expected error of type *fs.PathError,
+// got EOF`. For example:
+//
+//	pathErr := runtimex.AssertErrorAs[*fs.PathError](err)
+//
+// When assertions are disabled, it returns the zero value of T on mismatch.
+func AssertErrorAs[T error](err error) T {
+	var target T
+	if !errors.As(err, &target) && assertionsEnabled() {
+		assertionFailed(fmt.Errorf("expected error of type %v, got %v", reflect.TypeFor[T](), err))
+	}
+	return target
+}
+
+// AssertImplements panics unless v implements the interface I, in which case
+// it returns v converted to I. The value passed to `panic()` is an
+// [*AssertionError] whose message includes both types, e.g., `type int does
+// not implement io.Reader`. A nil v does not implement any interface.
+//
+// Passing a concrete type as I is a misuse. It behaves like a type assertion
+// to that concrete type, but the message wrongly speaks of implementing it.
+//
+// When assertions are disabled, it returns the zero value of I on mismatch.
+func AssertImplements[I any](v any) I {
+	iv, ok := v.(I)
+	if !ok && assertionsEnabled() {
+		assertionFailed(fmt.Errorf("type %T does not implement %v", v, reflect.TypeFor[I]()))
+	}
+	return iv
+}
+
+// AssertType panics unless the dynamic type of v is exactly T. The value passed
+// to `panic()` is an [*AssertionError] whose message includes both types, e.g.,
+// `expected type *bytes.Buffer, got *strings.Builder`, or `expected type
+// *bytes.Buffer, got nil` when v is nil.
+//
+// Unlike [AssertImplements], this function does not accept types that merely
+// implement T. Because the dynamic type of v is never an interface, passing an
+// interface type as T always fails. Use [AssertImplements] in such a case.
+func AssertType[T any](v any) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if err := checkType[T](v); err != nil {
+		assertionFailed(err)
+	}
+}
+
+// AssertTypeReturn is like [AssertType] but also returns v converted to T.
+// For example:
+//
+//	buf := runtimex.AssertTypeReturn[*bytes.Buffer](w)
+//
+// When assertions are disabled, it returns the zero value of T on mismatch.
+func AssertTypeReturn[T any](v any) T {
+	err := checkType[T](v)
+	if err != nil {
+		if assertionsEnabled() {
+			assertionFailed(err)
+		}
+		var zero T
+		return zero
+	}
+	return v.(T)
+}
+
+// checkType returns an error unless the dynamic type of v is exactly T.
+func checkType[T any](v any) error {
+	want := reflect.TypeFor[T]()
+	if v == nil {
+		return fmt.Errorf("expected type %v, got nil", want)
+	}
+	if got := reflect.TypeOf(v); got != want {
+		return fmt.Errorf("expected type %v, got %v", want, got)
+	}
+	return nil
+}
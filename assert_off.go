@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+//go:build runtimex_noassert
+
+package runtimex
+
+// assertionsCompiled is false when building with `-tags runtimex_noassert`.
+// Since the assertion functions test this constant before doing anything else,
+// the compiler reduces them to empty functions, which it can inline.
+const assertionsCompiled = false
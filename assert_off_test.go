@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+//go:build runtimex_noassert
+
+package runtimex
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoAssertAssertionsDoNotPanic(t *testing.T) {
+	assert.NotPanics(t, func() {
+		Assert(false)
+		Assertf(false, "format")
+		AssertEqual(1, 2)
+		AssertNotEqual(1, 1)
+		AssertNil(17)
+		AssertNotNil(nil)
+		AssertLen([]int{}, 1)
+		AssertLenAny(17, 1)
+		AssertSliceContains([]int{}, 1)
+		AssertMapHasKey(map[int]int{}, 1)
+		AssertErrorIs(nil, errors.New("test error"))
+		AssertErrorAs[*AssertionError](errors.New("test error"))
+		AssertPositive(0)
+		AssertNonNegative(-1)
+		AssertInRange(7, 0, 5)
+		AssertFinite(math.NaN())
+		(&AssertGroup{errs: []error{errors.New("test error")}}).Finish()
+		AssertSorted([]int{2, 1})
+		AssertSortedFunc([]int{1, 2}, func(a, b int) bool { return a > b })
+		AssertUnique([]int{1, 1})
+		AssertZero(1)
+		AssertNotZero(0)
+		func() { var once Once; once.Do(); once.Do() }()
+		(&GoroutineChecker{}).AssertSameGoroutine()
+		AssertPanics(func() {})
+		AssertImplements[error](17)
+		AssertEqualSlice([]int{1}, []int{2})
+		AssertEqualMap(map[int]int{1: 1}, map[int]int{})
+		AssertLazy(false, func() string { return "message" })
+		AssertContextAlive(canceledContext())
+		AssertType[int]("17")
+		AssertTypeReturn[int]("17")
+		AssertDisjoint([]int{1}, []int{1})
+		AssertSubset([]int{1}, []int{})
+		AssertTimeInRange(time.Unix(0, 0), time.Unix(1, 0), time.Unix(2, 0))
+		AssertBefore(time.Unix(1, 0), time.Unix(0, 0))
+		AssertAfter(time.Unix(0, 0), time.Unix(1, 0))
+		AssertMonotonic([]int{1, 1}, true)
+		AssertNotEmptyString("")
+		AssertNotEmptySlice([]int{})
+		AssertNotEmptyMap(map[int]int{})
+		AssertHasPrefix("a", "b")
+		AssertHasSuffix("a", "b")
+		AssertThat(1, func(int) bool { return false }, "message")
+		AssertEqualMapFunc(map[int]int{1: 1}, map[int]int{}, cmp.Compare[int])
+		AssertDeepEqual([]int{1}, []int{2})
+		AssertElementsMatch([]int{1}, []int{2})
+		AssertNonOverlappingRanges([][2]int{{9, 5}})
+		AssertKeysEqual(map[int]int{1: 1}, map[int]int{})
+		AssertRegexpMatchString(`[`, "a")
+		AssertChannelClosed(make(chan int))
+		AssertAll([]int{1}, func(int) bool { return false })
+		AssertAny([]int{}, func(int) bool { return true })
+		AssertStepAtLeast([]int{1, 2}, 5)
+		AssertCapAtLeast([]int{}, 1)
+		AssertKeysEqualFunc(map[int]int{1: 1}, map[int]int{}, cmp.Compare[int])
+	})
+}
+
+func TestNoAssertPanicOnErrorStillWorks(t *testing.T) {
+	t.Run("with nil error returns values", func(t *testing.T) {
+		v1, v2 := PanicOnError2("a", 1, nil)
+		assert.Equal(t, "a", v1)
+		assert.Equal(t, 1, v2)
+	})
+
+	t.Run("with non-nil error panics", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		ae := recoverAssertionError(func() {
+			PanicOnError1("a", expectedErr)
+		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+}
+
+func TestNoAssertUnreachableStillPanics(t *testing.T) {
+	assert.PanicsWithError(t, "unreachable code reached: reason", func() {
+		AssertUnreachable("reason")
+	})
+}
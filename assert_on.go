@@ -0,0 +1,8 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+//go:build !runtimex_noassert
+
+package runtimex
+
+// assertionsCompiled is true unless building with `-tags runtimex_noassert`.
+const assertionsCompiled = true
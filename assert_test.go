@@ -0,0 +1,567 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// comparableStruct is a comparable struct used for testing.
+type comparableStruct struct {
+	Name  string
+	Value int
+}
+
+func TestAssertEqual(t *testing.T) {
+	t.Run("with equal values does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertEqual(3, 3)
+			AssertEqual("a", "a")
+			AssertEqual(comparableStruct{"a", 1}, comparableStruct{"a", 1})
+		})
+	})
+
+	t.Run("with different ints panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected equal, got 3 and 5", func() {
+			AssertEqual(3, 5)
+		})
+	})
+
+	t.Run("with different strings panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected equal, got a and b", func() {
+			AssertEqual("a", "b")
+		})
+	})
+
+	t.Run("with different structs panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected equal, got {a 1} and {a 2}", func() {
+			AssertEqual(comparableStruct{"a", 1}, comparableStruct{"a", 2})
+		})
+	})
+}
+
+func TestAssertDeepEqual(t *testing.T) {
+	type nested struct {
+		Items []comparableStruct
+		Tags  map[string][]string
+	}
+
+	newNested := func() nested {
+		return nested{
+			Items: []comparableStruct{{Name: "a", Value: 1}},
+			Tags:  map[string][]string{"k": {"v1", "v2"}},
+		}
+	}
+
+	t.Run("with deeply equal values does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertDeepEqual(newNested(), newNested())
+			AssertDeepEqual([]int{1, 2}, []int{1, 2})
+			AssertDeepEqual(nil, nil)
+		})
+	})
+
+	t.Run("with different values panics showing both values", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected deep equal, got []int{1, 2} and []int{1, 3}", func() {
+			AssertDeepEqual([]int{1, 2}, []int{1, 3})
+		})
+	})
+
+	t.Run("with different nested values panics", func(t *testing.T) {
+		other := newNested()
+		other.Tags["k"][1] = "v3"
+		assert.Panics(t, func() {
+			AssertDeepEqual(newNested(), other)
+		})
+	})
+
+	t.Run("with overlong values truncates the message", func(t *testing.T) {
+		got, want := make([]int, 100), make([]int, 100)
+		want[99] = 1
+		err := recoverError(func() {
+			AssertDeepEqual(got, want)
+		})
+		rendering := fmt.Sprintf("%#v", got)[:maxShownValueRunes] + "..."
+		assert.EqualError(t, err, "expected deep equal, got "+rendering+" and "+rendering)
+	})
+}
+
+func TestAssertNotEqual(t *testing.T) {
+	t.Run("with different values does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertNotEqual(3, 5)
+			AssertNotEqual("a", "b")
+			AssertNotEqual(comparableStruct{"a", 1}, comparableStruct{"a", 2})
+		})
+	})
+
+	t.Run("with equal ints panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected not equal, got 5 and 5", func() {
+			AssertNotEqual(5, 5)
+		})
+	})
+
+	t.Run("with equal strings panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected not equal, got a and a", func() {
+			AssertNotEqual("a", "a")
+		})
+	})
+
+	t.Run("with equal structs panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected not equal, got {a 1} and {a 1}", func() {
+			AssertNotEqual(comparableStruct{"a", 1}, comparableStruct{"a", 1})
+		})
+	})
+}
+
+func TestAssertZero(t *testing.T) {
+	t.Run("with zero values does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertZero(0)
+			AssertZero("")
+			AssertZero(comparableStruct{})
+		})
+	})
+
+	t.Run("with non-zero int panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected zero value, got 17", func() {
+			AssertZero(17)
+		})
+	})
+
+	t.Run("with non-empty string panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected zero value, got a", func() {
+			AssertZero("a")
+		})
+	})
+
+	t.Run("with non-zero struct panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected zero value, got {a 0}", func() {
+			AssertZero(comparableStruct{Name: "a"})
+		})
+	})
+}
+
+func TestAssertNotZero(t *testing.T) {
+	t.Run("with non-zero values does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertNotZero(17)
+			AssertNotZero("a")
+			AssertNotZero(comparableStruct{Value: 1})
+		})
+	})
+
+	t.Run("with zero int panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected non-zero value, got 0", func() {
+			AssertNotZero(0)
+		})
+	})
+
+	t.Run("with empty string panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected non-zero value, got ", func() {
+			AssertNotZero("")
+		})
+	})
+
+	t.Run("with zero struct panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected non-zero value, got { 0}", func() {
+			AssertNotZero(comparableStruct{})
+		})
+	})
+}
+
+func TestAssertNil(t *testing.T) {
+	t.Run("with nil values does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertNil(nil)
+			AssertNil((*bytes.Buffer)(nil))
+			AssertNil(map[string]int(nil))
+			AssertNil([]int(nil))
+			AssertNil((chan int)(nil))
+			AssertNil((func())(nil))
+		})
+	})
+
+	t.Run("with typed nil stored in any does not panic", func(t *testing.T) {
+		var v any = (*bytes.Buffer)(nil)
+		assert.NotPanics(t, func() {
+			AssertNil(v)
+		})
+	})
+
+	t.Run("with non-nil pointer panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected nil, got *bytes.Buffer", func() {
+			AssertNil(&bytes.Buffer{})
+		})
+	})
+
+	t.Run("with non-nillable value panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected nil, got int", func() {
+			AssertNil(0)
+		})
+	})
+}
+
+func TestAssertNotNil(t *testing.T) {
+	t.Run("with non-nil values does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertNotNil(&bytes.Buffer{})
+			AssertNotNil(map[string]int{})
+			AssertNotNil([]int{})
+			AssertNotNil(make(chan int))
+			AssertNotNil(func() {})
+			AssertNotNil(0)
+		})
+	})
+
+	t.Run("with nil panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected non-nil value", func() {
+			AssertNotNil(nil)
+		})
+	})
+
+	t.Run("with typed nil stored in any panics", func(t *testing.T) {
+		var v any = (*bytes.Buffer)(nil)
+		assert.PanicsWithError(t, "expected non-nil value", func() {
+			AssertNotNil(v)
+		})
+	})
+
+	t.Run("with nil slice panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected non-nil value", func() {
+			AssertNotNil([]int(nil))
+		})
+	})
+}
+
+func TestAssertLen(t *testing.T) {
+	t.Run("with expected length does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertLen([]int{1, 2, 3}, 3)
+		})
+	})
+
+	t.Run("with nil slice has length zero", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertLen([]int(nil), 0)
+		})
+	})
+
+	t.Run("with unexpected length panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected length 3, got 5", func() {
+			AssertLen([]int{1, 2, 3, 4, 5}, 3)
+		})
+	})
+}
+
+func TestAssertCapAtLeast(t *testing.T) {
+	t.Run("with sufficient capacity does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertCapAtLeast(make([]byte, 0, 64), 64)
+			AssertCapAtLeast(make([]byte, 0, 128), 64)
+		})
+	})
+
+	t.Run("with insufficient capacity panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected capacity >= 64, got 32", func() {
+			AssertCapAtLeast(make([]byte, 32), 64)
+		})
+	})
+
+	t.Run("with nil slice panics unless minCap is zero", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertCapAtLeast[byte](nil, 0)
+		})
+		assert.PanicsWithError(t, "expected capacity >= 1, got 0", func() {
+			AssertCapAtLeast[byte](nil, 1)
+		})
+	})
+}
+
+func TestAssertLenAny(t *testing.T) {
+	ch := make(chan int, 4)
+	ch <- 1
+	ch <- 2
+
+	t.Run("with expected length does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertLenAny([]int{1, 2, 3}, 3)
+			AssertLenAny([]int(nil), 0)
+			AssertLenAny([2]int{}, 2)
+			AssertLenAny(&[2]int{}, 2)
+			AssertLenAny(map[string]int{"a": 1}, 1)
+			AssertLenAny(map[string]int(nil), 0)
+			AssertLenAny("abcd", 4)
+			AssertLenAny(ch, 2)
+		})
+	})
+
+	t.Run("with unexpected length panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected length 3, got 5", func() {
+			AssertLenAny([]int{1, 2, 3, 4, 5}, 3)
+		})
+		assert.PanicsWithError(t, "expected length 3, got 2", func() {
+			AssertLenAny([2]int{}, 3)
+		})
+		assert.PanicsWithError(t, "expected length 3, got 1", func() {
+			AssertLenAny(map[string]int{"a": 1}, 3)
+		})
+		assert.PanicsWithError(t, "expected length 3, got 4", func() {
+			AssertLenAny("abcd", 3)
+		})
+		assert.PanicsWithError(t, "expected length 3, got 2", func() {
+			AssertLenAny(ch, 3)
+		})
+	})
+
+	t.Run("with a type without length panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "type int has no length", func() {
+			AssertLenAny(17, 0)
+		})
+		assert.PanicsWithError(t, "type *int has no length", func() {
+			AssertLenAny(new(int), 0)
+		})
+		assert.PanicsWithError(t, "type <nil> has no length", func() {
+			AssertLenAny(nil, 0)
+		})
+	})
+}
+
+func TestAssertNotEmptyString(t *testing.T) {
+	t.Run("with non-empty string does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertNotEmptyString("name")
+		})
+	})
+
+	t.Run("with empty string panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected non-empty string", func() {
+			AssertNotEmptyString("")
+		})
+	})
+}
+
+func TestAssertNotEmptySlice(t *testing.T) {
+	t.Run("with non-empty slice does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertNotEmptySlice([]int{1})
+		})
+	})
+
+	t.Run("with empty slice panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected non-empty slice", func() {
+			AssertNotEmptySlice([]int{})
+		})
+	})
+
+	t.Run("with nil slice panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected non-empty slice", func() {
+			AssertNotEmptySlice[int](nil)
+		})
+	})
+}
+
+func TestAssertNotEmptyMap(t *testing.T) {
+	t.Run("with non-empty map does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertNotEmptyMap(map[string]int{"a": 1})
+		})
+	})
+
+	t.Run("with empty map panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected non-empty map", func() {
+			AssertNotEmptyMap(map[string]int{})
+		})
+	})
+
+	t.Run("with nil map panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected non-empty map", func() {
+			AssertNotEmptyMap[string, int](nil)
+		})
+	})
+}
+
+func TestAssertSliceContains(t *testing.T) {
+	t.Run("with present value does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertSliceContains([]int{1, 2, 3}, 2)
+		})
+	})
+
+	t.Run("with absent value panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "value 4 not found in slice", func() {
+			AssertSliceContains([]int{1, 2, 3}, 4)
+		})
+	})
+
+	t.Run("with empty slice panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "value a not found in slice", func() {
+			AssertSliceContains([]string{}, "a")
+		})
+	})
+}
+
+func TestAssertMapHasKey(t *testing.T) {
+	t.Run("with present key does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertMapHasKey(map[string]int{"foo": 0}, "foo")
+		})
+	})
+
+	t.Run("with absent key panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "key bar not present in map", func() {
+			AssertMapHasKey(map[string]int{"foo": 0}, "bar")
+		})
+	})
+
+	t.Run("with empty map panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "key foo not present in map", func() {
+			AssertMapHasKey(map[string]int(nil), "foo")
+		})
+	})
+}
+
+func TestAssertErrorIs(t *testing.T) {
+	t.Run("with matching error does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertErrorIs(io.EOF, io.EOF)
+		})
+	})
+
+	t.Run("with wrapped matching error does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertErrorIs(fmt.Errorf("reading: %w", io.EOF), io.EOF)
+		})
+	})
+
+	t.Run("with non-matching error panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected error matching EOF, got unexpected EOF", func() {
+			AssertErrorIs(io.ErrUnexpectedEOF, io.EOF)
+		})
+	})
+
+	t.Run("with nil error panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected error matching EOF, got <nil>", func() {
+			AssertErrorIs(nil, io.EOF)
+		})
+	})
+}
+
+func TestAssertErrorAs(t *testing.T) {
+	t.Run("with matching wrapped error returns the concrete error", func(t *testing.T) {
+		expectedErr := &fs.PathError{Op: "open", Path: "/nonexistent", Err: fs.ErrNotExist}
+		var pathErr *fs.PathError
+		assert.NotPanics(t, func() {
+			pathErr = AssertErrorAs[*fs.PathError](fmt.Errorf("loading: %w", expectedErr))
+		})
+		assert.Same(t, expectedErr, pathErr)
+	})
+
+	t.Run("with non-matching error panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected error of type *fs.PathError, got EOF", func() {
+			AssertErrorAs[*fs.PathError](io.EOF)
+		})
+	})
+
+	t.Run("with nil error panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected error of type *fs.PathError, got <nil>", func() {
+			AssertErrorAs[*fs.PathError](nil)
+		})
+	})
+
+	t.Run("with non-matching error and assertions disabled returns zero value", func(t *testing.T) {
+		SetAssertionsEnabled(false)
+		defer SetAssertionsEnabled(true)
+		assert.Nil(t, AssertErrorAs[*fs.PathError](errors.New("test error")))
+	})
+}
+
+func TestAssertImplements(t *testing.T) {
+	t.Run("with a satisfying type returns the interface value", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		var r io.Reader
+		assert.NotPanics(t, func() {
+			r = AssertImplements[io.Reader](buf)
+		})
+		assert.Same(t, buf, r)
+	})
+
+	t.Run("with a non-satisfying type panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "type int does not implement io.Reader", func() {
+			AssertImplements[io.Reader](17)
+		})
+	})
+
+	t.Run("with nil panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "type <nil> does not implement io.Reader", func() {
+			AssertImplements[io.Reader](nil)
+		})
+	})
+}
+
+func TestAssertType(t *testing.T) {
+	t.Run("with matching type does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertType[*bytes.Buffer](&bytes.Buffer{})
+		})
+	})
+
+	t.Run("with mismatched type panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected type *bytes.Buffer, got *strings.Builder", func() {
+			AssertType[*bytes.Buffer](&strings.Builder{})
+		})
+	})
+
+	t.Run("with interface implementation panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected type io.Reader, got *bytes.Buffer", func() {
+			AssertType[io.Reader](&bytes.Buffer{})
+		})
+	})
+
+	t.Run("with nil value panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected type *bytes.Buffer, got nil", func() {
+			AssertType[*bytes.Buffer](nil)
+		})
+	})
+}
+
+func TestAssertTypeReturn(t *testing.T) {
+	t.Run("with matching type returns the value", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		var got *bytes.Buffer
+		assert.NotPanics(t, func() {
+			got = AssertTypeReturn[*bytes.Buffer](buf)
+		})
+		assert.Same(t, buf, got)
+	})
+
+	t.Run("with mismatched type panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected type int, got int64", func() {
+			AssertTypeReturn[int](int64(17))
+		})
+	})
+
+	t.Run("with nil value panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected type *bytes.Buffer, got nil", func() {
+			AssertTypeReturn[*bytes.Buffer](nil)
+		})
+	})
+
+	t.Run("when disabled returns the zero value on mismatch", func(t *testing.T) {
+		SetAssertionsEnabled(false)
+		defer SetAssertionsEnabled(true)
+		var got int
+		assert.NotPanics(t, func() {
+			got = AssertTypeReturn[int]("17")
+		})
+		assert.Equal(t, 0, got)
+	})
+}
@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// AssertionMode selects what happens when an assertion function fails.
+type AssertionMode int32
+
+const (
+	// ModePanic passes an [*AssertionError] to `panic()`. This is the default.
+	ModePanic AssertionMode = iota
+
+	// ModeWarn logs the [*AssertionError] using the function configured with
+	// [SetWarnLogger] and returns normally from the assertion function.
+	ModeWarn
+
+	// ModeDisabled returns immediately without checking the arguments.
+	ModeDisabled
+)
+
+// assertionMode is the [AssertionMode] configured using [SetAssertionMode]. We
+// use [ModePanic] as the zero value such that assertions panic by default.
+var assertionMode atomic.Int32
+
+// SetAssertionMode configures how the assertion functions (e.g., [Assert],
+// [AssertEqual], [AssertNil], [AssertLen]) behave. The default is [ModePanic].
+// This function is goroutine safe.
+//
+// Use [ModeWarn] to trade strictness for availability, e.g., during an incident,
+// where logging a broken invariant is preferable to crashing. Note that the code
+// following a failed assertion then runs with the invariant broken. Assertion
+// functions returning a value (e.g., [AssertErrorAs]) return the zero value.
+//
+// To remove assertions entirely at compile time, build with the
+// `runtimex_noassert` tag, in which case this function has no effect.
+//
+// The mode does not affect the PanicOnErrorN family, which guards actual
+// error values rather than pure invariants and therefore always panics.
+func SetAssertionMode(mode AssertionMode) {
+	assertionMode.Store(int32(mode))
+}
+
+// SetAssertionsEnabled enables or disables the assertion functions (e.g.,
+// [Assert], [AssertEqual], [AssertNil], [AssertLen]). When disabled, they
+// return immediately without checking their arguments. Assertions are
+// enabled by default. This function is goroutine safe.
+//
+// It is equivalent to calling [SetAssertionMode] with [ModePanic] when
+// enabled is true and with [ModeDisabled] otherwise.
+//
+// Disabling assertions trades safety for speed in performance critical
+// code paths. It does not affect the PanicOnErrorN family, which guards
+// actual error values rather than pure invariants.
+func SetAssertionsEnabled(enabled bool) {
+	if enabled {
+		SetAssertionMode(ModePanic)
+		return
+	}
+	SetAssertionMode(ModeDisabled)
+}
+
+// assertionsEnabled returns whether assertions are enabled.
+//
+// The assertion functions should test [assertionsCompiled] before calling
+// this function, e.g., `if !assertionsCompiled || !assertionsEnabled()`,
+// since the compiler does not fold the constant through the inlined call
+// and would otherwise not inline them with `-tags runtimex_noassert`.
+func assertionsEnabled() bool {
+	return assertionsCompiled && AssertionMode(assertionMode.Load()) != ModeDisabled
+}
+
+// warnLogger is the logger configured using [SetWarnLogger].
+var warnLogger func(err error)
+
+// SetWarnLogger configures the function that logs failed assertions when
+// using [ModeWarn]. The fn argument is the [*AssertionError] that would
+// otherwise be passed to `panic()`.
+//
+// Passing nil restores the default behavior of logging using [log.Print].
+// This function is not goroutine safe and should be called at program startup.
+func SetWarnLogger(fn func(err error)) {
+	warnLogger = fn
+}
+
+// warnAssertionFailure logs err using the configured warn logger.
+func warnAssertionFailure(err error) {
+	if warnLogger == nil {
+		logPrint(err)
+		return
+	}
+	warnLogger(err)
+}
+
+// captureStack is true when stack capture has been enabled using [SetCaptureStack].
+var captureStack atomic.Bool
+
+// SetCaptureStack enables or disables capturing the stack trace when an
+// assertion fails or a PanicOnErrorN function panics. The captured stack
+// is available through the methods of the [*AssertionError] passed to
+// `panic()`. It also enables [CatchPanic] and [WithRecover] to return a
+// [*RecoveredError]. Stack capture is disabled by default to avoid its
+// overhead. This function is goroutine safe.
+func SetCaptureStack(enabled bool) {
+	captureStack.Store(enabled)
+}
+
+var (
+	// failureHooks contains the hooks registered using [OnAssertionFailure].
+	failureHooks []func(err error)
+
+	// failureHooksMu protects failureHooks.
+	failureHooksMu sync.Mutex
+)
+
+// OnAssertionFailure registers fn to be called when an assertion function
+// (e.g., [Assert], [AssertEqual]) fails or a PanicOnErrorN function (e.g.,
+// [PanicOnError0]) receives a non-nil error, just before it panics. The fn
+// argument is the [*AssertionError] that is about to be passed to `panic()`.
+//
+// Use this function to emit metrics or structured logs before a failed
+// invariant crashes the program. Hooks run in registration order. Passing
+// nil clears all the registered hooks. This function is goroutine safe.
+//
+// Hooks cannot suppress the panic, which happens after all hooks returned.
+// Hooks must not panic themselves, since that would replace the panic value.
+func OnAssertionFailure(fn func(err error)) {
+	defer failureHooksMu.Unlock()
+	failureHooksMu.Lock()
+	if fn == nil {
+		failureHooks = nil
+		return
+	}
+	failureHooks = append(failureHooks, fn)
+}
+
+// runFailureHooks calls the hooks registered using [OnAssertionFailure].
+func runFailureHooks(err error) {
+	failureHooksMu.Lock()
+	hooks := failureHooks
+	failureHooksMu.Unlock()
+	for _, fn := range hooks {
+		fn(err)
+	}
+}
+
+// TB is the subset of [testing.TB] used by [UseTestingTB].
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}
+
+// testingTB is the [TB] configured using [UseTestingTB] or nil.
+var testingTB TB
+
+// UseTestingTB redirects failed assertions to tb.Fatalf rather than to `panic()`,
+// such that invariant violations inside library code fail the calling test
+// cleanly. It returns a function restoring the previous behavior:
+//
+//	defer runtimex.UseTestingTB(t)()
+//
+// Since the [testing] package attributes the failure to the assertion function
+// rather than to its caller, the message passed to Fatalf is always prefixed
+// with the file name and line of the code invoking the assertion function,
+// e.g., `config.go:42: assertion failed`, as if [SetIncludeCaller] was enabled.
+//
+// The PanicOnErrorN family (e.g., [PanicOnError0]) and the helpers built on top
+// of it (e.g., [Must]) also call tb.Fatalf with a non-nil error. Since the code
+// following them assumes that the error is nil, they still panic if tb.Fatalf
+// returns, which does not happen with [testing.TB], whose Fatalf stops the test.
+//
+// Since this function modifies global state, it is only safe to use in tests
+// that do not run in parallel and that assert from the test goroutine.
+func UseTestingTB(tb TB) (restore func()) {
+	prev := testingTB
+	testingTB = tb
+	return func() {
+		testingTB = prev
+	}
+}
+
+// includeCaller is true when caller annotation has been enabled using [SetIncludeCaller].
+var includeCaller atomic.Bool
+
+// SetIncludeCaller enables or disables prefixing the message of failed
+// assertions with the file name and line of the code invoking the assertion
+// function, e.g., `config.go:42: assertion failed`. Likewise, it prefixes the
+// errors passed to `panic()` by the PanicOnErrorN family with the file name
+// and line of the code invoking the PanicOnErrorN function or the helpers
+// built on top of it (e.g., [Must]). This is cheaper than capturing the
+// whole stack with [SetCaptureStack] and is disabled by default. This
+// function is goroutine safe.
+func SetIncludeCaller(enabled bool) {
+	includeCaller.Store(enabled)
+}
@@ -0,0 +1,401 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAssertionsEnabled(t *testing.T) {
+	// Make sure we restore the default after the test
+	defer SetAssertionsEnabled(true)
+
+	t.Run("when disabled assertions do not panic", func(t *testing.T) {
+		SetAssertionsEnabled(false)
+		assert.NotPanics(t, func() {
+			Assert(false)
+			Assertf(false, "format")
+			AssertEqual(1, 2)
+			AssertNotEqual(1, 1)
+			AssertNil(17)
+			AssertNotNil(nil)
+			AssertLen([]int{}, 1)
+			AssertLenAny(17, 1)
+			AssertSliceContains([]int{}, 1)
+			AssertMapHasKey(map[int]int{}, 1)
+			AssertErrorIs(nil, errors.New("test error"))
+			AssertErrorAs[*AssertionError](errors.New("test error"))
+			AssertPositive(0)
+			AssertNonNegative(-1)
+			AssertInRange(7, 0, 5)
+			AssertFinite(math.NaN())
+			(&AssertGroup{errs: []error{errors.New("test error")}}).Finish()
+			AssertSorted([]int{2, 1})
+			AssertSortedFunc([]int{1, 2}, func(a, b int) bool { return a > b })
+			AssertUnique([]int{1, 1})
+			AssertZero(1)
+			AssertNotZero(0)
+			func() { var once Once; once.Do(); once.Do() }()
+			(&GoroutineChecker{}).AssertSameGoroutine()
+			AssertPanics(func() {})
+			AssertImplements[error](17)
+			AssertEqualSlice([]int{1}, []int{2})
+			AssertEqualMap(map[int]int{1: 1}, map[int]int{})
+			AssertLazy(false, func() string { return "message" })
+			AssertContextAlive(canceledContext())
+			AssertType[int]("17")
+			AssertTypeReturn[int]("17")
+			AssertDisjoint([]int{1}, []int{1})
+			AssertSubset([]int{1}, []int{})
+			AssertTimeInRange(time.Unix(0, 0), time.Unix(1, 0), time.Unix(2, 0))
+			AssertBefore(time.Unix(1, 0), time.Unix(0, 0))
+			AssertAfter(time.Unix(0, 0), time.Unix(1, 0))
+			AssertMonotonic([]int{1, 1}, true)
+			AssertNotEmptyString("")
+			AssertNotEmptySlice([]int{})
+			AssertNotEmptyMap(map[int]int{})
+			AssertHasPrefix("a", "b")
+			AssertHasSuffix("a", "b")
+			AssertThat(1, func(int) bool { return false }, "message")
+			AssertEqualMapFunc(map[int]int{1: 1}, map[int]int{}, cmp.Compare[int])
+			AssertDeepEqual([]int{1}, []int{2})
+			AssertElementsMatch([]int{1}, []int{2})
+			AssertNonOverlappingRanges([][2]int{{9, 5}})
+			AssertKeysEqual(map[int]int{1: 1}, map[int]int{})
+			AssertRegexpMatchString(`[`, "a")
+			AssertChannelClosed(make(chan int))
+			AssertAll([]int{1}, func(int) bool { return false })
+			AssertAny([]int{}, func(int) bool { return true })
+			AssertStepAtLeast([]int{1, 2}, 5)
+			AssertCapAtLeast([]int{}, 1)
+			AssertKeysEqualFunc(map[int]int{1: 1}, map[int]int{}, cmp.Compare[int])
+		})
+	})
+
+	t.Run("when disabled PanicOnErrorN still panics", func(t *testing.T) {
+		SetAssertionsEnabled(false)
+		expectedErr := errors.New("test error")
+		ae := recoverAssertionError(func() {
+			PanicOnError0(expectedErr)
+		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+
+	t.Run("when re-enabled assertions panic", func(t *testing.T) {
+		SetAssertionsEnabled(false)
+		SetAssertionsEnabled(true)
+		assert.PanicsWithError(t, "assertion failed", func() {
+			Assert(false)
+		})
+	})
+}
+
+func TestSetAssertionMode(t *testing.T) {
+	// Make sure we restore the defaults after the test
+	defer SetAssertionMode(ModePanic)
+	defer SetWarnLogger(nil)
+
+	t.Run("with ModePanic assertions panic", func(t *testing.T) {
+		SetAssertionMode(ModePanic)
+		assert.PanicsWithError(t, "expected equal, got 1 and 2", func() {
+			AssertEqual(1, 2)
+		})
+	})
+
+	t.Run("with ModeWarn assertions warn and continue", func(t *testing.T) {
+		SetAssertionMode(ModeWarn)
+		var warnings []error
+		SetWarnLogger(func(err error) {
+			warnings = append(warnings, err)
+		})
+		var continued bool
+		assert.NotPanics(t, func() {
+			Assert(false)
+			AssertEqual(1, 2)
+			Assert(true)
+			continued = true
+		})
+		assert.True(t, continued)
+		if assert.Len(t, warnings, 2) {
+			assert.True(t, IsAssertionError(warnings[0]))
+			assert.EqualError(t, warnings[0], "assertion failed")
+			assert.EqualError(t, warnings[1], "expected equal, got 1 and 2")
+		}
+	})
+
+	t.Run("with ModeWarn and no warn logger uses log.Print", func(t *testing.T) {
+		SetAssertionMode(ModeWarn)
+		SetWarnLogger(nil)
+		originalLogPrint := logPrint
+		defer func() { logPrint = originalLogPrint }()
+		var logged []any
+		logPrint = func(v ...any) {
+			logged = append(logged, v...)
+		}
+		assert.NotPanics(t, func() {
+			Assert(false)
+		})
+		if assert.Len(t, logged, 1) {
+			assert.True(t, IsAssertionError(logged[0]))
+		}
+	})
+
+	t.Run("with ModeWarn PanicOnErrorN still panics", func(t *testing.T) {
+		SetAssertionMode(ModeWarn)
+		expectedErr := errors.New("test error")
+		ae := recoverAssertionError(func() {
+			PanicOnError0(expectedErr)
+		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+
+	t.Run("with ModeWarn value-returning assertions return the zero value", func(t *testing.T) {
+		SetAssertionMode(ModeWarn)
+		SetWarnLogger(func(err error) {})
+		assert.Nil(t, AssertErrorAs[*AssertionError](errors.New("test error")))
+	})
+
+	t.Run("with ModeDisabled assertions do nothing", func(t *testing.T) {
+		SetAssertionMode(ModeDisabled)
+		var warned bool
+		SetWarnLogger(func(err error) {
+			warned = true
+		})
+		assert.NotPanics(t, func() {
+			Assert(false)
+			AssertEqual(1, 2)
+		})
+		assert.False(t, warned)
+	})
+
+	t.Run("SetAssertionsEnabled selects ModePanic or ModeDisabled", func(t *testing.T) {
+		SetAssertionMode(ModeWarn)
+		SetAssertionsEnabled(false)
+		assert.Equal(t, ModeDisabled, AssertionMode(assertionMode.Load()))
+		SetAssertionsEnabled(true)
+		assert.Equal(t, ModePanic, AssertionMode(assertionMode.Load()))
+	})
+}
+
+func TestOnAssertionFailure(t *testing.T) {
+	// Make sure we clear the hooks after the test
+	defer OnAssertionFailure(nil)
+
+	var calls []string
+	var hookErr error
+	OnAssertionFailure(func(err error) {
+		calls = append(calls, "first")
+		hookErr = err
+	})
+	OnAssertionFailure(func(err error) {
+		calls = append(calls, "second")
+	})
+
+	t.Run("hooks are not called when the assertion holds", func(t *testing.T) {
+		calls, hookErr = nil, nil
+		Assert(true)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("hooks are called in order before panicking", func(t *testing.T) {
+		calls, hookErr = nil, nil
+		var recovered any
+		func() {
+			defer func() {
+				recovered = recover()
+			}()
+			AssertEqual(1, 2)
+		}()
+		assert.Equal(t, []string{"first", "second"}, calls)
+		assert.Same(t, recovered, hookErr)
+		assert.EqualError(t, hookErr, "expected equal, got 1 and 2")
+	})
+
+	t.Run("hooks are called before PanicOnError0 panics", func(t *testing.T) {
+		calls, hookErr = nil, nil
+		expectedErr := errors.New("test error")
+		var recovered any
+		func() {
+			defer func() {
+				recovered = recover()
+			}()
+			PanicOnError0(expectedErr)
+		}()
+		assert.Equal(t, []string{"first", "second"}, calls)
+		assert.Same(t, recovered, hookErr)
+		assert.ErrorIs(t, hookErr, expectedErr)
+	})
+
+	t.Run("hooks are not called when PanicOnError0 gets a nil error", func(t *testing.T) {
+		calls, hookErr = nil, nil
+		PanicOnError0(nil)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("passing nil clears the hooks", func(t *testing.T) {
+		calls, hookErr = nil, nil
+		OnAssertionFailure(nil)
+		assert.Panics(t, func() {
+			Assert(false)
+		})
+		assert.Empty(t, calls)
+	})
+}
+
+// fakeTB is a [TB] recording the calls to Fatalf.
+type fakeTB struct {
+	messages []string
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Fatalf(format string, args ...any) {
+	tb.messages = append(tb.messages, fmt.Sprintf(format, args...))
+}
+
+func TestUseTestingTB(t *testing.T) {
+	t.Run("failed assertions call Fatalf", func(t *testing.T) {
+		tb := &fakeTB{}
+		restore := UseTestingTB(tb)
+		defer restore()
+		var line int
+		assert.NotPanics(t, func() {
+			Assert(true)
+			_, _, line, _ = runtime.Caller(0)
+			Assert(false)     // must be on the line following runtime.Caller
+			AssertEqual(1, 2) // must be on the line following Assert(false)
+		})
+		expected := []string{
+			fmt.Sprintf("assertconfig_test.go:%d: assertion failed", line+1),
+			fmt.Sprintf("assertconfig_test.go:%d: expected equal, got 1 and 2", line+2),
+		}
+		assert.Equal(t, expected, tb.messages)
+	})
+
+	t.Run("with SetIncludeCaller the location is not repeated", func(t *testing.T) {
+		defer SetIncludeCaller(false)
+		SetIncludeCaller(true)
+		tb := &fakeTB{}
+		restore := UseTestingTB(tb)
+		defer restore()
+		var line int
+		assert.NotPanics(t, func() {
+			_, _, line, _ = runtime.Caller(0)
+			Assert(false) // must be on the line following runtime.Caller
+		})
+		expected := fmt.Sprintf("assertconfig_test.go:%d: assertion failed", line+1)
+		assert.Equal(t, []string{expected}, tb.messages)
+	})
+
+	t.Run("PanicOnErrorN calls Fatalf and then panics", func(t *testing.T) {
+		tb := &fakeTB{}
+		restore := UseTestingTB(tb)
+		defer restore()
+		expectedErr := errors.New("test error")
+		var line int
+		ae := recoverAssertionError(func() {
+			PanicOnError0(nil)
+			_, _, line, _ = runtime.Caller(0)
+			PanicOnError0(expectedErr) // must be on the line following runtime.Caller
+		})
+		assert.Same(t, expectedErr, ae.Err)
+		expected := fmt.Sprintf("assertconfig_test.go:%d: test error", line+1)
+		assert.Equal(t, []string{expected}, tb.messages)
+	})
+
+	t.Run("restore reverts to panicking", func(t *testing.T) {
+		tb := &fakeTB{}
+		restore := UseTestingTB(tb)
+		restore()
+		assert.PanicsWithError(t, "assertion failed", func() {
+			Assert(false)
+		})
+		assert.Empty(t, tb.messages)
+	})
+}
+
+func TestSetIncludeCaller(t *testing.T) {
+	// Make sure we restore the default after the test
+	defer SetIncludeCaller(false)
+
+	t.Run("when enabled prefixes the caller file and line", func(t *testing.T) {
+		SetIncludeCaller(true)
+		var line int
+		r := AssertPanics(func() {
+			_, _, line, _ = runtime.Caller(0)
+			AssertEqual(1, 2) // must be on the line following runtime.Caller
+		})
+		expected := fmt.Sprintf("assertconfig_test.go:%d: expected equal, got 1 and 2", line+1)
+		assert.EqualError(t, r.(error), expected)
+	})
+
+	t.Run("when enabled prefixes PanicOnError0 errors with the caller", func(t *testing.T) {
+		SetIncludeCaller(true)
+		expectedErr := errors.New("test error")
+		var line int
+		ae := recoverAssertionError(func() {
+			_, _, line, _ = runtime.Caller(0)
+			PanicOnError0(expectedErr) // must be on the line following runtime.Caller
+		})
+		expected := fmt.Sprintf("assertconfig_test.go:%d: test error", line+1)
+		assert.EqualError(t, ae, expected)
+		assert.ErrorIs(t, ae, expectedErr)
+	})
+
+	t.Run("when enabled prefixes PanicOnError1 errors with the caller", func(t *testing.T) {
+		SetIncludeCaller(true)
+		var line int
+		ae := recoverAssertionError(func() {
+			_, _, line, _ = runtime.Caller(0)
+			PanicOnError1(17, errors.New("test error")) // must be on the line following runtime.Caller
+		})
+		expected := fmt.Sprintf("assertconfig_test.go:%d: test error", line+1)
+		assert.EqualError(t, ae, expected)
+	})
+
+	t.Run("when enabled wrappers prefix the code invoking them", func(t *testing.T) {
+		SetIncludeCaller(true)
+		expectedErr := errors.New("test error")
+		getter := MustInit(func() (int, error) { return 0, expectedErr })
+		// Each function must fit on a single line, such that the line where
+		// the function starts is the line where it invokes the wrapper.
+		cases := map[string]func(){
+			"Must":                    func() { Must(17, expectedErr) },
+			"MustCompile":             func() { MustCompile(17, expectedErr) },
+			"MustClose":               func() { MustClose(&fakeCloser{err: expectedErr}) },
+			"MustParseURL":            func() { MustParseURL("\x00") },
+			"MustMap":                 func() { MustMap([]string{"x"}, strconv.Atoi) },
+			"MustInit":                func() { getter() },
+			"Result.Unwrap":           func() { Err[int](expectedErr).Unwrap() },
+			"AssertRegexpMatchString": func() { AssertRegexpMatchString(`[`, "a") },
+		}
+		for name, fn := range cases {
+			pc := reflect.ValueOf(fn).Pointer()
+			_, line := runtime.FuncForPC(pc).FileLine(pc)
+			ae := recoverAssertionError(fn)
+			prefix := fmt.Sprintf("assertconfig_test.go:%d: ", line)
+			assert.True(t, strings.HasPrefix(ae.Error(), prefix), "%s: %s", name, ae.Error())
+		}
+	})
+
+	t.Run("when disabled does not prefix the caller", func(t *testing.T) {
+		SetIncludeCaller(false)
+		assert.PanicsWithError(t, "expected equal, got 1 and 2", func() {
+			AssertEqual(1, 2)
+		})
+		assert.PanicsWithError(t, "test error", func() {
+			PanicOnError0(errors.New("test error"))
+		})
+	})
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AssertGroup collects failed assertions and reports all of them at once
+// when calling [*AssertGroup.Finish]. This is useful in validation code
+// where knowing every failure helps more than stopping at the first one:
+//
+//	var ag runtimex.AssertGroup
+//	ag.True(cfg.Port > 0, "invalid port %d", cfg.Port)
+//	ag.NoError(validateHost(cfg.Host), "invalid host %q", cfg.Host)
+//	ag.Finish()
+//
+// The zero value is ready to use. An AssertGroup is not goroutine safe.
+type AssertGroup struct {
+	errs []error
+}
+
+// True records a failure if cond is false, using an error
+// constructed using [fmt.Errorf] with the given format and args.
+func (ag *AssertGroup) True(cond bool, format string, args ...any) {
+	if !cond {
+		ag.errs = append(ag.errs, fmt.Errorf(format, args...))
+	}
+}
+
+// NoError records a failure if err is not nil, wrapping err with
+// the given format and args, such that the message is "<context>: <err>".
+func (ag *AssertGroup) NoError(err error, format string, args ...any) {
+	if err != nil {
+		ag.errs = append(ag.errs, fmt.Errorf(format+": %w", append(args, err)...))
+	}
+}
+
+// Finish panics if there were failures. The value passed to `panic()` is an
+// [*AssertionError] wrapping all the failures joined using [errors.Join].
+func (ag *AssertGroup) Finish() {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if len(ag.errs) > 0 {
+		assertionFailed(errors.Join(ag.errs...))
+	}
+}
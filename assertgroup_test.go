@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertGroup(t *testing.T) {
+	t.Run("with zero failures does not panic", func(t *testing.T) {
+		var ag AssertGroup
+		ag.True(true, "not failing")
+		ag.NoError(nil, "not failing")
+		assert.NotPanics(t, func() {
+			ag.Finish()
+		})
+	})
+
+	t.Run("with one failure panics", func(t *testing.T) {
+		var ag AssertGroup
+		ag.True(false, "invalid port %d", 0)
+		ag.NoError(nil, "not failing")
+		assert.PanicsWithError(t, "invalid port 0", func() {
+			ag.Finish()
+		})
+	})
+
+	t.Run("with several failures panics with all of them", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		var ag AssertGroup
+		ag.True(false, "invalid port %d", 0)
+		ag.NoError(expectedErr, "invalid host %q", "")
+		ag.True(false, "invalid timeout")
+		var recovered any
+		func() {
+			defer func() {
+				recovered = recover()
+			}()
+			ag.Finish()
+		}()
+		assert.True(t, IsAssertionError(recovered))
+		err := recovered.(error)
+		assert.EqualError(t, err, "invalid port 0\ninvalid host \"\": test error\ninvalid timeout")
+		assert.True(t, errors.Is(err, expectedErr))
+	})
+}
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// AssertionError is the error passed to `panic()` by the assertion functions
+// (e.g., [Assert], [AssertEqual]) when an invariant does not hold and by the
+// PanicOnErrorN family (e.g., [PanicOnError1]) when err is not nil.
+//
+// Code that recovers from panics can use [IsAssertionError] to distinguish
+// failed assertions from other panics (e.g., from third-party code).
+type AssertionError struct {
+	// Err is the underlying error describing the failed assertion.
+	Err error
+
+	// stack contains the program counters captured when
+	// the assertion failed if [SetCaptureStack] is enabled.
+	stack []uintptr
+}
+
+var _ error = &AssertionError{}
+
+// Error implements error.
+func (e *AssertionError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *AssertionError) Unwrap() error {
+	return e.Err
+}
+
+// StackTrace returns the program counters of the stack frames leading to the
+// failed assertion, starting from the function invoking the assertion. The
+// returned slice is empty unless stack capture is enabled using [SetCaptureStack].
+func (e *AssertionError) StackTrace() []uintptr {
+	return e.stack
+}
+
+// Stack returns a human readable representation of [*AssertionError.StackTrace]
+// with a function name and a tab-indented file:line location per frame.
+func (e *AssertionError) Stack() string {
+	return formatStack(e.stack)
+}
+
+// formatStack formats the program counters in stack with a function
+// name and a tab-indented file:line location per frame.
+func formatStack(stack []uintptr) string {
+	var sb strings.Builder
+	if len(stack) > 0 {
+		frames := runtime.CallersFrames(stack)
+		for {
+			frame, more := frames.Next()
+			fmt.Fprintf(&sb, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+			if !more {
+				break
+			}
+		}
+	}
+	return sb.String()
+}
+
+var _ slog.LogValuer = &AssertionError{}
+
+// LogValue implements [slog.LogValuer] such that logging an [*AssertionError]
+// using [log/slog] produces a group with an "error" attribute containing the
+// error message. When the stack has been captured using [SetCaptureStack],
+// the group also contains the "caller" file:line location and the "stack".
+func (e *AssertionError) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("error", e.Err.Error())}
+	if len(e.stack) > 0 {
+		frame, _ := runtime.CallersFrames(e.stack).Next()
+		attrs = append(attrs,
+			slog.String("caller", fmt.Sprintf("%s:%d", frame.File, frame.Line)),
+			slog.String("stack", e.Stack()),
+		)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// IsAssertionError returns whether r, typically the value returned by
+// `recover()`, is an error wrapping an [*AssertionError].
+func IsAssertionError(r any) bool {
+	err, ok := r.(error)
+	if !ok {
+		return false
+	}
+	var ae *AssertionError
+	return errors.As(err, &ae)
+}
+
+// assertionFailedSkip is the number of stack frames to skip with [runtime.Callers]
+// such that the captured stack starts at the function invoking the assertion
+// function. Since [runtime.Caller] does not count itself, it needs one less.
+const assertionFailedSkip = 3
+
+// assertionFailed panics with an [*AssertionError] wrapping err after
+// running the hooks registered with [OnAssertionFailure]. When a [TB]
+// is configured with [UseTestingTB], it calls Fatalf rather than panicking,
+// prefixing the message with the caller location like [SetIncludeCaller].
+// With [ModeWarn], it logs the [*AssertionError] and returns.
+//
+// This function must be called directly by the exported assertion functions
+// for [assertionFailedSkip] to point to the right stack frame.
+func assertionFailed(err error) {
+	countAssertionFailure()
+	var location string
+	if _, file, line, ok := runtime.Caller(assertionFailedSkip - 1); ok {
+		location = fmt.Sprintf("%s:%d: ", filepath.Base(file), line)
+	}
+	if includeCaller.Load() {
+		err = fmt.Errorf("%s%w", location, err)
+		location = "" // avoid repeating it when calling Fatalf
+	}
+	ae := &AssertionError{Err: err}
+	if captureStack.Load() {
+		pcs := make([]uintptr, 64)
+		n := runtime.Callers(assertionFailedSkip, pcs)
+		ae.stack = pcs[:n]
+	}
+	runFailureHooks(ae)
+	if AssertionMode(assertionMode.Load()) == ModeWarn {
+		warnAssertionFailure(ae)
+		return
+	}
+	if tb := testingTB; tb != nil {
+		tb.Helper()
+		tb.Fatalf("%s%s", location, ae.Error())
+		return
+	}
+	panic(ae)
+}
+
+// panicOnError implements the PanicOnErrorN family and its wrappers: it counts
+// the call like [countPanicOnError] and calls [panicOnErrorFailed] if err is not
+// nil. The skip argument is the number of stack frames of this package between
+// the code invoking the family and panicOnError, i.e., 1 when called directly
+// by an exported function. Wrappers add one for each frame of their own.
+//
+// The PanicOnErrorN functions call it only when err is not nil or metrics are
+// enabled, such that their nil error path is cheap enough to be inlined. We
+// mark it noinline to make sure it does not increase the cost of that path.
+//
+//go:noinline
+func panicOnError(skip int, err error) {
+	countPanicOnError(err)
+	if err != nil {
+		panicOnErrorFailed(skip+1, err)
+	}
+}
+
+// panicOnErrorf is like [panicOnError] but wraps err like [PanicOnError0f].
+func panicOnErrorf(skip int, err error, format string, args ...any) {
+	countPanicOnError(err)
+	if err != nil {
+		panicOnErrorFailed(skip+1, fmt.Errorf(format+": %w", append(args, err)...))
+	}
+}
+
+// panicOnErrorFailed panics with an [*AssertionError] wrapping err after
+// running the hooks registered with [OnAssertionFailure]. The PanicOnErrorN
+// family calls it when err is not nil. When a [TB] is configured with
+// [UseTestingTB], it calls Fatalf like [assertionFailed]. Unlike it, it panics
+// regardless of the [AssertionMode] and also when Fatalf returns, since the
+// code following a PanicOnErrorN call assumes that err is nil.
+//
+// The skip argument is like in [panicOnError] and allows [SetIncludeCaller]
+// and [SetCaptureStack] to point to the code invoking the family rather than
+// to the wrappers of this package (e.g., [Must]).
+func panicOnErrorFailed(skip int, err error) {
+	var location string
+	if _, file, line, ok := runtime.Caller(skip + 1); ok {
+		location = fmt.Sprintf("%s:%d: ", filepath.Base(file), line)
+	}
+	if includeCaller.Load() {
+		err = fmt.Errorf("%s%w", location, err)
+		location = "" // avoid repeating it when calling Fatalf
+	}
+	ae := &AssertionError{Err: err}
+	if captureStack.Load() {
+		pcs := make([]uintptr, 64)
+		n := runtime.Callers(skip+2, pcs)
+		ae.stack = pcs[:n]
+	}
+	runFailureHooks(ae)
+	if tb := testingTB; tb != nil {
+		tb.Helper()
+		tb.Fatalf("%s%s", location, ae.Error())
+	}
+	panic(ae) // also with a [TB] whose Fatalf returns
+}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertionError(t *testing.T) {
+	t.Run("Error returns the underlying error message", func(t *testing.T) {
+		err := &AssertionError{Err: errors.New("test error")}
+		assert.Equal(t, "test error", err.Error())
+	})
+
+	t.Run("Unwrap returns the underlying error", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		err := &AssertionError{Err: expectedErr}
+		assert.Equal(t, expectedErr, err.Unwrap())
+		assert.True(t, errors.Is(err, expectedErr))
+	})
+}
+
+func TestIsAssertionError(t *testing.T) {
+	t.Run("with an assertion panic returns true", func(t *testing.T) {
+		defer func() {
+			assert.True(t, IsAssertionError(recover()))
+		}()
+		Assert(false)
+	})
+
+	t.Run("with a wrapped assertion error returns true", func(t *testing.T) {
+		err := fmt.Errorf("context: %w", &AssertionError{Err: errors.New("test error")})
+		assert.True(t, IsAssertionError(err))
+	})
+
+	t.Run("with a PanicOnError0 panic returns true", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		defer func() {
+			r := recover()
+			assert.True(t, IsAssertionError(r))
+			assert.ErrorIs(t, r.(error), expectedErr)
+		}()
+		PanicOnError0(expectedErr)
+	})
+
+	t.Run("with a third-party error panic returns false", func(t *testing.T) {
+		defer func() {
+			assert.False(t, IsAssertionError(recover()))
+		}()
+		panic(errors.New("test error"))
+	})
+
+	t.Run("with a non-error value returns false", func(t *testing.T) {
+		assert.False(t, IsAssertionError("test"))
+		assert.False(t, IsAssertionError(nil))
+	})
+}
+
+// recoverAssertionError calls fn and returns the [*AssertionError] it panicked with.
+func recoverAssertionError(fn func()) (ae *AssertionError) {
+	defer func() {
+		ae = recover().(*AssertionError)
+	}()
+	fn()
+	return
+}
+
+func TestSetCaptureStack(t *testing.T) {
+	// Make sure we restore the default after the test
+	defer SetCaptureStack(false)
+
+	t.Run("when enabled captures the stack", func(t *testing.T) {
+		SetCaptureStack(true)
+		ae := recoverAssertionError(func() {
+			Assert(false)
+		})
+		pcs := ae.StackTrace()
+		assert.NotEmpty(t, pcs)
+		frame, _ := runtime.CallersFrames(pcs).Next()
+		assert.True(t, strings.HasSuffix(frame.Function, "TestSetCaptureStack.func1.1"), frame.Function)
+		assert.True(t, strings.HasPrefix(ae.Stack(), frame.Function+"\n\t"))
+		assert.Contains(t, ae.Stack(), "assertionerror_test.go:")
+	})
+
+	t.Run("when enabled captures the stack of PanicOnError0", func(t *testing.T) {
+		SetCaptureStack(true)
+		ae := recoverAssertionError(func() {
+			PanicOnError0(errors.New("test error"))
+		})
+		frame, _ := runtime.CallersFrames(ae.StackTrace()).Next()
+		assert.True(t, strings.HasSuffix(frame.Function, "TestSetCaptureStack.func2.1"), frame.Function)
+	})
+
+	t.Run("when enabled captures the stack of the code invoking Must", func(t *testing.T) {
+		SetCaptureStack(true)
+		ae := recoverAssertionError(func() {
+			Must(17, errors.New("test error"))
+		})
+		frame, _ := runtime.CallersFrames(ae.StackTrace()).Next()
+		assert.True(t, strings.HasSuffix(frame.Function, "TestSetCaptureStack.func3.1"), frame.Function)
+	})
+
+	t.Run("when disabled does not capture the stack", func(t *testing.T) {
+		SetCaptureStack(false)
+		ae := recoverAssertionError(func() {
+			Assert(false)
+		})
+		assert.Empty(t, ae.StackTrace())
+		assert.Empty(t, ae.Stack())
+	})
+}
+
+// logAssertionError logs ae using a JSON [slog.Logger] and returns the
+// attributes of the "assertion" group parsed from the emitted record.
+func logAssertionError(t *testing.T, ae *AssertionError) map[string]any {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("invariant violation", "assertion", ae)
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	group, _ := record["assertion"].(map[string]any)
+	return group
+}
+
+func TestAssertionErrorLogValue(t *testing.T) {
+	// Make sure we restore the default after the test
+	defer SetCaptureStack(false)
+
+	t.Run("without stack logs the error", func(t *testing.T) {
+		SetCaptureStack(false)
+		ae := recoverAssertionError(func() {
+			Assert(false)
+		})
+		group := logAssertionError(t, ae)
+		assert.Equal(t, map[string]any{"error": "assertion failed"}, group)
+	})
+
+	t.Run("with stack also logs the caller and the stack", func(t *testing.T) {
+		SetCaptureStack(true)
+		ae := recoverAssertionError(func() {
+			Assert(false)
+		})
+		group := logAssertionError(t, ae)
+		assert.Equal(t, "assertion failed", group["error"])
+		assert.Contains(t, group["caller"], "assertionerror_test.go:")
+		assert.Equal(t, ae.Stack(), group["stack"])
+	})
+}
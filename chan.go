@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// errChannelClosed is the error used when a channel is unexpectedly closed.
+var errChannelClosed = errors.New("channel closed unexpectedly")
+
+// MustReceive receives a value from ch, blocking until a value is available,
+// and panics if ch is closed. The value passed to `panic()` is an [*AssertionError]
+// whose message is `channel closed unexpectedly`.
+func MustReceive[T any](ch <-chan T) T {
+	v, ok := <-ch
+	if !ok {
+		panicOnError(1, errChannelClosed)
+	}
+	return v
+}
+
+// MustReceiveWithin is like [MustReceive] but also panics if no value is
+// received within the given timeout. In such a case, the value passed to
+// `panic()` is an [*AssertionError] like `no value received within 1s`.
+func MustReceiveWithin[T any](ch <-chan T, d time.Duration) T {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	var (
+		v  T
+		ok bool
+	)
+	select {
+	case v, ok = <-ch:
+		if !ok {
+			panicOnError(1, errChannelClosed)
+		}
+	case <-timer.C:
+		panicOnError(1, fmt.Errorf("no value received within %v", d))
+	}
+	return v
+}
+
+// AssertChannelClosed panics unless ch is closed and drained. To check, it
+// performs a non-blocking receive, which succeeds immediately on a closed and
+// drained channel. Otherwise, the value passed to `panic()` is an [*AssertionError]
+// whose message is `expected closed channel, received a value` if the receive
+// yields a value, or `expected closed channel, receive would block` if ch is
+// open and empty.
+//
+// Note that this check is destructive: when ch contains buffered values, or an
+// open channel has a sender ready, the received value is lost. Use it only
+// where a value would be a bug. There is no AssertChannelOpen, since Go does
+// not provide a way to check that a channel is open without receiving.
+func AssertChannelClosed[T any](ch <-chan T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	select {
+	case _, ok := <-ch:
+		if ok {
+			assertionFailed(errors.New("expected closed channel, received a value"))
+		}
+	default:
+		assertionFailed(errors.New("expected closed channel, receive would block"))
+	}
+}
+
+// MustDrainClosed receives from ch until it is closed and returns the values
+// it received, or nil if ch was already closed and drained. It panics if ch
+// is not closed within the given timeout, in which case the value passed to
+// `panic()` is an [*AssertionError] like `channel not closed within 1s` and the values
+// received so far are lost. Use it to collect the results of producers that
+// must close ch when done.
+func MustDrainClosed[T any](ch <-chan T, d time.Duration) (values []T) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return values
+			}
+			values = append(values, v)
+		case <-timer.C:
+			panicOnError(1, fmt.Errorf("channel not closed within %v", d))
+		}
+	}
+}
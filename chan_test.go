@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMustReceive(t *testing.T) {
+	t.Run("with a value returns the value", func(t *testing.T) {
+		ch := make(chan int, 1)
+		ch <- 17
+		assert.Equal(t, 17, MustReceive(ch))
+	})
+
+	t.Run("with a closed channel panics", func(t *testing.T) {
+		ch := make(chan int)
+		close(ch)
+		assert.PanicsWithError(t, "channel closed unexpectedly", func() {
+			MustReceive(ch)
+		})
+	})
+
+	t.Run("with a closed channel panics with an AssertionError", func(t *testing.T) {
+		ch := make(chan int)
+		close(ch)
+		ae := recoverAssertionError(func() {
+			MustReceive(ch)
+		})
+		assert.Same(t, errChannelClosed, ae.Err)
+	})
+}
+
+func TestMustReceiveWithin(t *testing.T) {
+	t.Run("with a value returns the value", func(t *testing.T) {
+		ch := make(chan int, 1)
+		ch <- 17
+		assert.Equal(t, 17, MustReceiveWithin(ch, time.Second))
+	})
+
+	t.Run("with a closed channel panics", func(t *testing.T) {
+		ch := make(chan int)
+		close(ch)
+		assert.PanicsWithError(t, "channel closed unexpectedly", func() {
+			MustReceiveWithin(ch, time.Second)
+		})
+	})
+
+	t.Run("with a timeout panics", func(t *testing.T) {
+		ch := make(chan int)
+		assert.PanicsWithError(t, "no value received within 10ms", func() {
+			MustReceiveWithin(ch, 10*time.Millisecond)
+		})
+	})
+}
+
+func TestAssertChannelClosed(t *testing.T) {
+	t.Run("with a closed channel does not panic", func(t *testing.T) {
+		ch := make(chan int)
+		close(ch)
+		assert.NotPanics(t, func() {
+			AssertChannelClosed(ch)
+		})
+	})
+
+	t.Run("with a closed channel with buffered values panics", func(t *testing.T) {
+		ch := make(chan int, 1)
+		ch <- 17
+		close(ch)
+		assert.PanicsWithError(t, "expected closed channel, received a value", func() {
+			AssertChannelClosed(ch)
+		})
+	})
+
+	t.Run("with an open channel panics", func(t *testing.T) {
+		ch := make(chan int)
+		assert.PanicsWithError(t, "expected closed channel, receive would block", func() {
+			AssertChannelClosed(ch)
+		})
+	})
+}
+
+func TestMustDrainClosed(t *testing.T) {
+	t.Run("with a closed channel returns nil", func(t *testing.T) {
+		ch := make(chan int)
+		close(ch)
+		assert.Nil(t, MustDrainClosed(ch, time.Second))
+	})
+
+	t.Run("with a closed channel with buffered values returns them", func(t *testing.T) {
+		ch := make(chan int, 2)
+		ch <- 17
+		ch <- 42
+		close(ch)
+		assert.Equal(t, []int{17, 42}, MustDrainClosed(ch, time.Second))
+	})
+
+	t.Run("with a channel closed by a producer returns the values", func(t *testing.T) {
+		ch := make(chan int)
+		go func() {
+			defer close(ch)
+			for idx := range 3 {
+				ch <- idx
+			}
+		}()
+		assert.Equal(t, []int{0, 1, 2}, MustDrainClosed(ch, 10*time.Second))
+	})
+
+	t.Run("with an open channel panics on timeout", func(t *testing.T) {
+		ch := make(chan int)
+		assert.PanicsWithError(t, "channel not closed within 10ms", func() {
+			MustDrainClosed(ch, 10*time.Millisecond)
+		})
+	})
+}
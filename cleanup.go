@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+// PanicOnError1Cleanup is like [PanicOnError1] but calls cleanup before
+// panicking when err is not nil. The value passed to `panic()` is still an
+// [*AssertionError] wrapping err. This is useful to release a resource that
+// was acquired before the failing operation without adding a defer. Since Go
+// does not allow passing a multi-value call along with other arguments, you
+// need to pass v1 and err explicitly. For example:
+//
+//	conn := runtimex.PanicOnError1(net.Dial("tcp", addr))
+//	tlsConn, err := handshake(conn)
+//	tlsConn = runtimex.PanicOnError1Cleanup(tlsConn, err, func() { conn.Close() })
+//
+// The cleanup function must not panic, since that would replace err as the
+// value passed to `panic()`.
+func PanicOnError1Cleanup[T1 any](v1 T1, err error, cleanup func()) T1 {
+	countPanicOnError(err)
+	if err != nil {
+		cleanup()
+		panicOnErrorFailed(1, err)
+	}
+	return v1
+}
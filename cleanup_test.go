@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPanicOnError1Cleanup(t *testing.T) {
+	t.Run("with nil error returns the value without cleanup", func(t *testing.T) {
+		var calls int
+		v := PanicOnError1Cleanup("value", nil, func() { calls++ })
+		assert.Equal(t, "value", v)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("with non-nil error runs cleanup once and panics with the error", func(t *testing.T) {
+		var calls int
+		expectedErr := errors.New("test error")
+		ae := recoverAssertionError(func() {
+			PanicOnError1Cleanup("value", expectedErr, func() { calls++ })
+		})
+		assert.Same(t, expectedErr, ae.Err)
+		assert.Equal(t, 1, calls)
+	})
+}
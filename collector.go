@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCollector collects errors from independent fallible steps such that
+// you can report all of them at once using [*ErrorCollector.FatalIfAny]:
+//
+//	var ec runtimex.ErrorCollector
+//	ec.Add(loadConfig())
+//	ec.Add(openDatabase())
+//	ec.FatalIfAny("startup failed")
+//
+// The zero value is ready to use. An ErrorCollector is not goroutine safe.
+type ErrorCollector struct {
+	errs []error
+}
+
+// Add collects err, if not nil.
+func (ec *ErrorCollector) Add(err error) {
+	if err != nil {
+		ec.errs = append(ec.errs, err)
+	}
+}
+
+// FatalIfAny logs and exits like [LogFatalOnError0] if there are collected
+// errors. The logged error joins all the collected errors using [errors.Join]
+// and prefixes them with msg followed by a colon and a space.
+func (ec *ErrorCollector) FatalIfAny(msg string) {
+	if err := ec.join(); err != nil {
+		logFatalError(fmt.Errorf("%s: %w", msg, err))
+	}
+}
+
+// join returns the collected errors joined using [errors.Join] or nil.
+func (ec *ErrorCollector) join() error {
+	return errors.Join(ec.errs...)
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCollector(t *testing.T) {
+	// Save original logFatal and restore after each test
+	originalLogFatal := logFatal
+	defer func() { logFatal = originalLogFatal }()
+
+	var fatalCalled bool
+	var fatalValue any
+	logFatal = func(v ...any) {
+		fatalCalled = true
+		fatalValue = v[0]
+	}
+
+	// Reset mocks before each subtest
+	resetMocks := func() {
+		fatalCalled = false
+		fatalValue = nil
+	}
+
+	t.Run("with zero errors", func(t *testing.T) {
+		resetMocks()
+		var ec ErrorCollector
+		ec.Add(nil)
+		ec.FatalIfAny("startup failed")
+		assert.False(t, fatalCalled)
+	})
+
+	t.Run("with one error", func(t *testing.T) {
+		resetMocks()
+		err := errors.New("first")
+		var ec ErrorCollector
+		ec.Add(nil)
+		ec.Add(err)
+		ec.FatalIfAny("startup failed")
+		assert.True(t, fatalCalled)
+		assert.EqualError(t, fatalValue.(error), "startup failed: first")
+		assert.True(t, errors.Is(fatalValue.(error), err))
+	})
+
+	t.Run("with multiple errors", func(t *testing.T) {
+		resetMocks()
+		err1 := errors.New("first")
+		err2 := errors.New("second")
+		var ec ErrorCollector
+		ec.Add(err1)
+		ec.Add(nil)
+		ec.Add(err2)
+		ec.FatalIfAny("startup failed")
+		assert.True(t, fatalCalled)
+		assert.EqualError(t, fatalValue.(error), "startup failed: first\nsecond")
+		assert.True(t, errors.Is(fatalValue.(error), err1))
+		assert.True(t, errors.Is(fatalValue.(error), err2))
+	})
+}
@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import "context"
+
+// PanicOnError1Context is like [PanicOnError1] but also panics if ctx is done,
+// enforcing that an operation that cannot fail did not outlive its context.
+// It first panics with err, if not nil, and then with ctx.Err(), if not nil.
+// Otherwise, it returns the given value `v1`.
+func PanicOnError1Context[T1 any](ctx context.Context, v1 T1, err error) T1 {
+	if err == nil {
+		err = ctx.Err()
+	}
+	panicOnError(1, err)
+	return v1
+}
+
+// AssertContextAlive panics if ctx is done. The value passed to `panic()`
+// is an [*AssertionError] wrapping ctx.Err(), i.e., [context.Canceled] or
+// [context.DeadlineExceeded]. Use it to document program points that
+// should never be reached with a dead context.
+func AssertContextAlive(ctx context.Context) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if err := ctx.Err(); err != nil {
+		assertionFailed(err)
+	}
+}
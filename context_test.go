@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// canceledContext returns a context that has already been canceled.
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestPanicOnError1Context(t *testing.T) {
+	t.Run("with nil error and live context returns the value", func(t *testing.T) {
+		assert.Equal(t, "value", PanicOnError1Context(context.Background(), "value", nil))
+	})
+
+	t.Run("with non-nil error panics with the error", func(t *testing.T) {
+		ctx := canceledContext()
+		expectedErr := errors.New("test error")
+		ae := recoverAssertionError(func() {
+			PanicOnError1Context(ctx, "value", expectedErr)
+		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+
+	t.Run("with nil error and canceled context panics with the context error", func(t *testing.T) {
+		ctx := canceledContext()
+		ae := recoverAssertionError(func() {
+			PanicOnError1Context(ctx, "value", nil)
+		})
+		assert.Same(t, context.Canceled, ae.Err)
+	})
+}
+
+func TestAssertContextAlive(t *testing.T) {
+	t.Run("with background context does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertContextAlive(context.Background())
+		})
+	})
+
+	t.Run("with canceled context panics", func(t *testing.T) {
+		ctx := canceledContext()
+		r := AssertPanics(func() {
+			AssertContextAlive(ctx)
+		})
+		assert.True(t, IsAssertionError(r))
+		assert.True(t, errors.Is(r.(error), context.Canceled))
+	})
+
+	t.Run("with timed out context panics", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+		<-ctx.Done()
+		r := AssertPanics(func() {
+			AssertContextAlive(ctx)
+		})
+		assert.True(t, IsAssertionError(r))
+		assert.True(t, errors.Is(r.(error), context.DeadlineExceeded))
+	})
+}
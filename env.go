@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"fmt"
+	"os"
+)
+
+// MustGetEnv returns the value of the environment variable named by key and
+// panics if the variable is not set. If allowEmpty is false, it also panics
+// when the variable is set but empty. The value passed to `panic()` is an
+// error like `required environment variable FOO not set`.
+func MustGetEnv(key string, allowEmpty bool) string {
+	value, err := lookupRequiredEnv(key, allowEmpty)
+	panicOnError(1, err)
+	return value
+}
+
+// LogFatalOnMissingEnv is like [MustGetEnv] but logs the error and exits
+// like [LogFatalOnError0] instead of panicking.
+func LogFatalOnMissingEnv(key string, allowEmpty bool) string {
+	value, err := lookupRequiredEnv(key, allowEmpty)
+	LogFatalOnError0(err)
+	return value
+}
+
+// lookupRequiredEnv returns the value of the required environment variable
+// named by key or an error if the variable is unset, or empty and !allowEmpty.
+func lookupRequiredEnv(key string, allowEmpty bool) (string, error) {
+	value, found := os.LookupEnv(key)
+	if !found {
+		return "", fmt.Errorf("required environment variable %s not set", key)
+	}
+	if value == "" && !allowEmpty {
+		return "", fmt.Errorf("required environment variable %s is empty", key)
+	}
+	return value, nil
+}
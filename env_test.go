@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testEnvKey = "RUNTIMEX_TEST_ENV"
+
+func TestMustGetEnv(t *testing.T) {
+	t.Run("with set variable returns the value", func(t *testing.T) {
+		t.Setenv(testEnvKey, "value")
+		assert.Equal(t, "value", MustGetEnv(testEnvKey, false))
+	})
+
+	t.Run("with empty variable and allowEmpty returns empty", func(t *testing.T) {
+		t.Setenv(testEnvKey, "")
+		assert.Equal(t, "", MustGetEnv(testEnvKey, true))
+	})
+
+	t.Run("with empty variable and !allowEmpty panics", func(t *testing.T) {
+		t.Setenv(testEnvKey, "")
+		assert.PanicsWithError(t, "required environment variable RUNTIMEX_TEST_ENV is empty", func() {
+			MustGetEnv(testEnvKey, false)
+		})
+	})
+
+	t.Run("with unset variable panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "required environment variable RUNTIMEX_TEST_ENV not set", func() {
+			MustGetEnv(testEnvKey, true)
+		})
+	})
+}
+
+func TestLogFatalOnMissingEnv(t *testing.T) {
+	// Save original logFatal and restore after each test
+	originalLogFatal := logFatal
+	defer func() { logFatal = originalLogFatal }()
+
+	var fatalCalled bool
+	var fatalValue any
+	logFatal = func(v ...any) {
+		fatalCalled = true
+		fatalValue = v[0]
+	}
+
+	// Reset mocks before each subtest
+	resetMocks := func() {
+		fatalCalled = false
+		fatalValue = nil
+	}
+
+	t.Run("with set variable returns the value", func(t *testing.T) {
+		resetMocks()
+		t.Setenv(testEnvKey, "value")
+		assert.Equal(t, "value", LogFatalOnMissingEnv(testEnvKey, false))
+		assert.False(t, fatalCalled)
+	})
+
+	t.Run("with empty variable and allowEmpty returns empty", func(t *testing.T) {
+		resetMocks()
+		t.Setenv(testEnvKey, "")
+		assert.Equal(t, "", LogFatalOnMissingEnv(testEnvKey, true))
+		assert.False(t, fatalCalled)
+	})
+
+	t.Run("with empty variable and !allowEmpty calls logFatal", func(t *testing.T) {
+		resetMocks()
+		t.Setenv(testEnvKey, "")
+		LogFatalOnMissingEnv(testEnvKey, false)
+		assert.True(t, fatalCalled)
+		assert.EqualError(t, fatalValue.(error), "required environment variable RUNTIMEX_TEST_ENV is empty")
+	})
+
+	t.Run("with unset variable calls logFatal", func(t *testing.T) {
+		resetMocks()
+		LogFatalOnMissingEnv(testEnvKey, true)
+		assert.True(t, fatalCalled)
+		assert.EqualError(t, fatalValue.(error), "required environment variable RUNTIMEX_TEST_ENV not set")
+	})
+}
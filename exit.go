@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// osExit is a variable so we can replace it during testing.
+var osExit = os.Exit
+
+var (
+	// exitCleanups contains the functions registered using [RegisterExitCleanup].
+	exitCleanups []func()
+
+	// exitFlushers contains the functions registered using [RegisterFlushBeforeExit].
+	exitFlushers []func()
+
+	// exitCleanupsMu protects exitCleanups and exitFlushers.
+	exitCleanupsMu sync.Mutex
+)
+
+// RegisterExitCleanup registers fn to run before exiting because of an error,
+// e.g., from [ExitOnError] or [LogFatalOnError0]. This gives a best-effort
+// cleanup path (e.g., removing temporary files) for fatal exits, which skip
+// deferred functions. Cleanups run in reverse registration order, like deferred
+// functions, and each runs at most once. This function is goroutine safe.
+func RegisterExitCleanup(fn func()) {
+	defer exitCleanupsMu.Unlock()
+	exitCleanupsMu.Lock()
+	exitCleanups = append(exitCleanups, fn)
+}
+
+// RegisterFlushBeforeExit registers fn to run just before exiting because of
+// an error, after logging the error and after running the cleanups registered
+// with [RegisterExitCleanup]. Use it to flush buffered writers (e.g., a log
+// file wrapped by a [bufio.Writer]), such that the final fatal message is not
+// lost. Flushers run in registration order and each runs at most once. This
+// function is goroutine safe.
+func RegisterFlushBeforeExit(fn func()) {
+	defer exitCleanupsMu.Unlock()
+	exitCleanupsMu.Lock()
+	exitFlushers = append(exitFlushers, fn)
+}
+
+// exitHandler is the handler configured using [SetExitHandler].
+var exitHandler func(code int)
+
+// SetExitHandler configures the functions exiting because of an error (e.g.,
+// [ExitOnError], [LogFatalOnError0]) to call fn with the exit status code
+// rather than calling [os.Exit]. Use it to perform a graceful shutdown, e.g.,
+// draining connections, before exiting. The fn function runs after the functions
+// registered with [RegisterExitCleanup] and [RegisterFlushBeforeExit] and should
+// eventually call [os.Exit] itself, since the code following a fatal exit
+// assumes it does not return.
+//
+// Passing nil restores the default behavior of calling [os.Exit]. This
+// function is not goroutine safe and should be called at program startup.
+func SetExitHandler(fn func(code int)) {
+	exitHandler = fn
+}
+
+// exitProcess runs the registered exit cleanups and flushers and then exits with
+// code using the handler configured with [SetExitHandler] or [os.Exit].
+func exitProcess(code int) {
+	exitCleanupsMu.Lock()
+	cleanups, flushers := exitCleanups, exitFlushers
+	exitCleanups, exitFlushers = nil, nil
+	exitCleanupsMu.Unlock()
+	for idx := len(cleanups) - 1; idx >= 0; idx-- {
+		cleanups[idx]()
+	}
+	for _, fn := range flushers {
+		fn()
+	}
+	if exitHandler != nil {
+		exitHandler(code)
+		return
+	}
+	osExit(code)
+}
+
+// exitCodeMapper is the mapper configured using [SetExitCodeMapper].
+var exitCodeMapper func(err error) int
+
+// SetExitCodeMapper configures [ExitOnError] to compute the exit status code
+// by calling fn with the error. This allows declaring in a single place how
+// error categories map to exit codes, using [errors.Is] or [errors.As]:
+//
+//	runtimex.SetExitCodeMapper(func(err error) int {
+//		var configErr *ConfigError
+//		if errors.As(err, &configErr) {
+//			return 78 // EX_CONFIG
+//		}
+//		return 0
+//	})
+//
+// When fn returns zero, [ExitOnError] uses the default status code 1.
+//
+// Passing nil restores the default behavior of always exiting with status
+// code 1. This function is not goroutine safe and should be called at
+// program startup.
+func SetExitCodeMapper(fn func(err error) int) {
+	exitCodeMapper = fn
+}
+
+// exitCodeFor returns the exit status code for err.
+func exitCodeFor(err error) int {
+	if exitCodeMapper != nil {
+		if code := exitCodeMapper(err); code != 0 {
+			return code
+		}
+	}
+	return 1
+}
+
+// ExitOnError exits with status code 1 if err is not nil. Use
+// [SetExitCodeMapper] to select the status code depending on err.
+//
+// It is equivalent to:
+//
+//	if err != nil {
+//		os.Exit(1)
+//	}
+//
+// Use [LogFatalOnError0] instead when you also want to log err.
+func ExitOnError(err error) {
+	if err != nil {
+		exitProcess(exitCodeFor(err))
+	}
+}
+
+// ExitOnErrorWithCode exits with the given status code if err is not nil.
+//
+// It is equivalent to:
+//
+//	if err != nil {
+//		os.Exit(code)
+//	}
+//
+// This is useful for programs mapping failure classes to distinct
+// exit codes (e.g., the BSD sysexits convention).
+func ExitOnErrorWithCode(code int, err error) {
+	if err != nil {
+		exitProcess(code)
+	}
+}
+
+// ExitOnErrorWriter writes a message to w and exits with status code 1 if err
+// is not nil. The message consists of msgs joined by spaces, followed by a
+// colon, a space, err, and a newline. Without msgs, it is just err and a newline.
+//
+// For example:
+//
+//	runtimex.ExitOnErrorWriter(os.Stderr, err, "cannot open", path)
+//
+// writes "cannot open <path>: <err>\n" to the standard error and exits. Unlike
+// [LogFatalOnError0], the message does not include the [log] package prefix.
+func ExitOnErrorWriter(w io.Writer, err error, msgs ...string) {
+	if err != nil {
+		fmt.Fprintln(w, wrapFatalError(err, msgs...))
+		exitProcess(1)
+	}
+}
+
+// fatalWriter is the writer configured using [SetDefaultErrorWriter].
+var fatalWriter io.Writer = os.Stderr
+
+// SetDefaultErrorWriter configures the writer used by [FatalOnError] and by
+// [FormatJSON]. Passing nil restores the default, which is [os.Stderr]. This
+// function is not goroutine safe and should be called at program startup.
+func SetDefaultErrorWriter(w io.Writer) {
+	if w == nil {
+		w = os.Stderr
+	}
+	fatalWriter = w
+}
+
+// FatalOnError is like [ExitOnErrorWriter] but writes to the writer configured
+// using [SetDefaultErrorWriter], which is [os.Stderr] by default. Use it in
+// command line tools to emit plain error messages, without the date and time
+// prefix added by [LogFatalOnError0]. For example:
+//
+//	runtimex.FatalOnError(err, "cannot open", path)
+//
+// writes "cannot open <path>: <err>\n" and exits with status code 1.
+func FatalOnError(err error, msgs ...string) {
+	ExitOnErrorWriter(fatalWriter, err, msgs...)
+}
+
+// fatalSeparator is the separator configured using [SetFatalSeparator].
+var fatalSeparator string
+
+// SetFatalSeparator configures how the functions accepting msgs qualifiers
+// (e.g., [LogFatalOnErrorCode], [ExitOnErrorWriter], [FatalOnError]) assemble
+// the message. By default, they join msgs using spaces and separate them from
+// the error using a colon and a space, e.g., "cannot open file.txt: <err>".
+// With a non-empty sep, they use sep in both places, e.g., with " - ", the
+// message is "cannot open - file.txt - <err>".
+//
+// Passing an empty string restores the default behavior. This function is
+// not goroutine safe and should be called at program startup.
+func SetFatalSeparator(sep string) {
+	fatalSeparator = sep
+}
+
+// joinFatalMsgs joins msgs using the configured separator or spaces.
+func joinFatalMsgs(msgs ...string) string {
+	if fatalSeparator == "" {
+		return strings.Join(msgs, " ")
+	}
+	return strings.Join(msgs, fatalSeparator)
+}
+
+// wrapFatalError returns err prefixed by msgs joined using the configured
+// separator, or by msgs joined using spaces and a colon by default.
+func wrapFatalError(err error, msgs ...string) error {
+	if len(msgs) <= 0 {
+		return err
+	}
+	if fatalSeparator == "" {
+		return fmt.Errorf("%s: %w", joinFatalMsgs(msgs...), err)
+	}
+	return fmt.Errorf("%s%s%w", joinFatalMsgs(msgs...), fatalSeparator, err)
+}
@@ -0,0 +1,351 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testConfigError is an error type used for testing [SetExitCodeMapper].
+type testConfigError struct{}
+
+func (*testConfigError) Error() string {
+	return "invalid configuration"
+}
+
+func TestExitOnError(t *testing.T) {
+	// Save original osExit and restore after each test
+	originalOsExit := osExit
+	defer func() { osExit = originalOsExit }()
+
+	var exitCalled bool
+	var exitCode int
+	osExit = func(code int) {
+		exitCalled = true
+		exitCode = code
+	}
+
+	// Reset mocks before each subtest
+	resetMocks := func() {
+		exitCalled = false
+		exitCode = 0
+	}
+
+	t.Run("ExitOnError", func(t *testing.T) {
+		t.Run("with nil error", func(t *testing.T) {
+			resetMocks()
+			ExitOnError(nil)
+			assert.False(t, exitCalled)
+		})
+
+		t.Run("with non-nil error", func(t *testing.T) {
+			resetMocks()
+			ExitOnError(errors.New("exit"))
+			assert.True(t, exitCalled)
+			assert.Equal(t, 1, exitCode)
+		})
+
+		t.Run("with an exit code mapper", func(t *testing.T) {
+			SetExitCodeMapper(func(err error) int {
+				var configErr *testConfigError
+				if errors.As(err, &configErr) {
+					return 78
+				}
+				return 0
+			})
+			defer SetExitCodeMapper(nil)
+
+			t.Run("with a mapped error", func(t *testing.T) {
+				resetMocks()
+				ExitOnError(fmt.Errorf("loading: %w", &testConfigError{}))
+				assert.True(t, exitCalled)
+				assert.Equal(t, 78, exitCode)
+			})
+
+			t.Run("with an unmapped error", func(t *testing.T) {
+				resetMocks()
+				ExitOnError(errors.New("exit"))
+				assert.True(t, exitCalled)
+				assert.Equal(t, 1, exitCode)
+			})
+
+			t.Run("with nil error", func(t *testing.T) {
+				resetMocks()
+				ExitOnError(nil)
+				assert.False(t, exitCalled)
+			})
+		})
+	})
+
+	t.Run("ExitOnErrorWithCode", func(t *testing.T) {
+		t.Run("with nil error", func(t *testing.T) {
+			resetMocks()
+			ExitOnErrorWithCode(78, nil)
+			assert.False(t, exitCalled)
+		})
+
+		t.Run("with non-nil error", func(t *testing.T) {
+			resetMocks()
+			ExitOnErrorWithCode(78, errors.New("exit"))
+			assert.True(t, exitCalled)
+			assert.Equal(t, 78, exitCode)
+		})
+	})
+	t.Run("ExitOnErrorWriter", func(t *testing.T) {
+		t.Run("with nil error", func(t *testing.T) {
+			resetMocks()
+			var buf bytes.Buffer
+			ExitOnErrorWriter(&buf, nil, "cannot open", "file.txt")
+			assert.False(t, exitCalled)
+			assert.Empty(t, buf.Bytes())
+		})
+
+		t.Run("with non-nil error and msgs", func(t *testing.T) {
+			resetMocks()
+			var buf bytes.Buffer
+			ExitOnErrorWriter(&buf, errors.New("exit"), "cannot open", "file.txt")
+			assert.True(t, exitCalled)
+			assert.Equal(t, 1, exitCode)
+			assert.Equal(t, "cannot open file.txt: exit\n", buf.String())
+		})
+
+		t.Run("with non-nil error and no msgs", func(t *testing.T) {
+			resetMocks()
+			var buf bytes.Buffer
+			ExitOnErrorWriter(&buf, errors.New("exit"))
+			assert.True(t, exitCalled)
+			assert.Equal(t, 1, exitCode)
+			assert.Equal(t, "exit\n", buf.String())
+		})
+	})
+
+	t.Run("FatalOnError", func(t *testing.T) {
+		t.Run("the default writer is os.Stderr", func(t *testing.T) {
+			assert.Equal(t, io.Writer(os.Stderr), fatalWriter)
+		})
+
+		var buf bytes.Buffer
+		SetDefaultErrorWriter(&buf)
+		defer SetDefaultErrorWriter(nil)
+
+		t.Run("with nil error", func(t *testing.T) {
+			resetMocks()
+			buf.Reset()
+			FatalOnError(nil, "cannot open", "file.txt")
+			assert.False(t, exitCalled)
+			assert.Empty(t, buf.Bytes())
+		})
+
+		t.Run("with non-nil error and msgs", func(t *testing.T) {
+			resetMocks()
+			buf.Reset()
+			FatalOnError(errors.New("exit"), "cannot open", "file.txt")
+			assert.True(t, exitCalled)
+			assert.Equal(t, 1, exitCode)
+			assert.Equal(t, "cannot open file.txt: exit\n", buf.String())
+		})
+
+		t.Run("with non-nil error and no msgs", func(t *testing.T) {
+			resetMocks()
+			buf.Reset()
+			FatalOnError(errors.New("exit"))
+			assert.True(t, exitCalled)
+			assert.Equal(t, "exit\n", buf.String())
+		})
+
+		t.Run("passing nil restores os.Stderr", func(t *testing.T) {
+			SetDefaultErrorWriter(nil)
+			assert.Equal(t, io.Writer(os.Stderr), fatalWriter)
+		})
+	})
+}
+
+func TestRegisterExitCleanup(t *testing.T) {
+	// Save original state and restore after the test
+	originalLogPrint := logPrint
+	originalOsExit := osExit
+	defer func() {
+		logPrint = originalLogPrint
+		osExit = originalOsExit
+	}()
+
+	var events []string
+	logPrint = func(v ...any) {
+		events = append(events, "log")
+	}
+	osExit = func(code int) {
+		events = append(events, "exit")
+	}
+
+	t.Run("ExitOnError runs cleanups in reverse order before exiting", func(t *testing.T) {
+		events = nil
+		RegisterExitCleanup(func() { events = append(events, "first") })
+		RegisterExitCleanup(func() { events = append(events, "second") })
+		ExitOnError(nil)
+		assert.Empty(t, events)
+		ExitOnError(errors.New("exit"))
+		assert.Equal(t, []string{"second", "first", "exit"}, events)
+	})
+
+	t.Run("LogFatalOnError0 runs cleanups after logging and before exiting", func(t *testing.T) {
+		events = nil
+		RegisterExitCleanup(func() { events = append(events, "cleanup") })
+		LogFatalOnError0(errors.New("exit"))
+		assert.Equal(t, []string{"log", "cleanup", "exit"}, events)
+	})
+
+	t.Run("cleanups run at most once", func(t *testing.T) {
+		events = nil
+		ExitOnError(errors.New("exit"))
+		assert.Equal(t, []string{"exit"}, events)
+	})
+}
+
+func TestSetFatalSeparator(t *testing.T) {
+	// Save original state and restore after the test
+	originalOsExit := osExit
+	originalLogPrint := logPrint
+	defer func() {
+		osExit = originalOsExit
+		logPrint = originalLogPrint
+		SetFatalSeparator("")
+	}()
+	osExit = func(code int) {}
+
+	var printValue any
+	logPrint = func(v ...any) {
+		printValue = v[0]
+	}
+
+	t.Run("the default is unchanged", func(t *testing.T) {
+		SetFatalSeparator("")
+		var buf bytes.Buffer
+		ExitOnErrorWriter(&buf, errors.New("exit"), "cannot open", "file.txt")
+		assert.Equal(t, "cannot open file.txt: exit\n", buf.String())
+	})
+
+	t.Run("with a custom separator in ExitOnErrorWriter", func(t *testing.T) {
+		SetFatalSeparator(" - ")
+		var buf bytes.Buffer
+		ExitOnErrorWriter(&buf, errors.New("exit"), "cannot open", "file.txt")
+		assert.Equal(t, "cannot open - file.txt - exit\n", buf.String())
+	})
+
+	t.Run("with a custom separator in LogFatalOnErrorCode", func(t *testing.T) {
+		SetFatalSeparator(" - ")
+		err := errors.New("exit")
+		LogFatalOnErrorCode(78, err, "loading", "config")
+		assert.EqualError(t, printValue.(error), "loading - config - exit")
+		assert.ErrorIs(t, printValue.(error), err)
+	})
+
+	t.Run("with a custom separator and no msgs", func(t *testing.T) {
+		SetFatalSeparator(" - ")
+		var buf bytes.Buffer
+		ExitOnErrorWriter(&buf, errors.New("exit"))
+		assert.Equal(t, "exit\n", buf.String())
+	})
+}
+
+func TestSetExitHandler(t *testing.T) {
+	// Save original state and restore after the test
+	originalOsExit := osExit
+	originalLogPrint := logPrint
+	defer func() {
+		osExit = originalOsExit
+		logPrint = originalLogPrint
+		SetExitHandler(nil)
+	}()
+
+	var osExitCalled bool
+	osExit = func(code int) {
+		osExitCalled = true
+	}
+	logPrint = func(v ...any) {}
+
+	var events []string
+	var handlerCode int
+	SetExitHandler(func(code int) {
+		events = append(events, "handler")
+		handlerCode = code
+	})
+
+	t.Run("ExitOnError calls the handler rather than exiting", func(t *testing.T) {
+		events, handlerCode, osExitCalled = nil, 0, false
+		ExitOnError(errors.New("exit"))
+		assert.Equal(t, []string{"handler"}, events)
+		assert.Equal(t, 1, handlerCode)
+		assert.False(t, osExitCalled)
+	})
+
+	t.Run("LogFatalOnError0 calls the handler after the cleanups", func(t *testing.T) {
+		events, handlerCode, osExitCalled = nil, 0, false
+		RegisterExitCleanup(func() {
+			events = append(events, "cleanup")
+		})
+		LogFatalOnError0(errors.New("fatal"))
+		assert.Equal(t, []string{"cleanup", "handler"}, events)
+		assert.Equal(t, 1, handlerCode)
+		assert.False(t, osExitCalled)
+	})
+
+	t.Run("with nil error does not call the handler", func(t *testing.T) {
+		events, handlerCode, osExitCalled = nil, 0, false
+		ExitOnError(nil)
+		assert.Nil(t, events)
+	})
+
+	t.Run("passing nil restores os.Exit", func(t *testing.T) {
+		events, handlerCode, osExitCalled = nil, 0, false
+		SetExitHandler(nil)
+		ExitOnError(errors.New("exit"))
+		assert.Nil(t, events)
+		assert.True(t, osExitCalled)
+	})
+}
+
+func TestRegisterFlushBeforeExit(t *testing.T) {
+	// Save original state and restore after the test
+	originalOsExit := osExit
+	originalLogPrint := logPrint
+	defer func() {
+		osExit = originalOsExit
+		logPrint = originalLogPrint
+	}()
+
+	var events []string
+	osExit = func(code int) {
+		events = append(events, "exit")
+	}
+	logPrint = func(v ...any) {
+		events = append(events, "log")
+	}
+
+	t.Run("flushers run after logging and cleanups and before exiting", func(t *testing.T) {
+		events = nil
+		RegisterFlushBeforeExit(func() {
+			events = append(events, "flush 1")
+		})
+		RegisterFlushBeforeExit(func() {
+			events = append(events, "flush 2")
+		})
+		RegisterExitCleanup(func() {
+			events = append(events, "cleanup")
+		})
+		LogFatalOnError0(errors.New("fatal"))
+		assert.Equal(t, []string{"log", "cleanup", "flush 1", "flush 2", "exit"}, events)
+	})
+
+	t.Run("flushers run at most once", func(t *testing.T) {
+		events = nil
+		ExitOnError(errors.New("exit"))
+		assert.Equal(t, []string{"exit"}, events)
+	})
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+)
+
+// GoroutineChecker asserts that an object documented as single-goroutine-only
+// is only used by the goroutine that created it:
+//
+//	type conn struct {
+//		gc *runtimex.GoroutineChecker
+//		// ...
+//	}
+//
+//	func (c *conn) Write(data []byte) {
+//		c.gc.AssertSameGoroutine()
+//		// ...
+//	}
+//
+// Because Go does not expose goroutine IDs, the checker parses them from the
+// output of [runtime.Stack], which is slow. Use it only for debugging.
+type GoroutineChecker struct {
+	id uint64
+}
+
+// NewGoroutineChecker returns a [*GoroutineChecker] bound to the current goroutine.
+func NewGoroutineChecker() *GoroutineChecker {
+	return &GoroutineChecker{id: currentGoroutineID()}
+}
+
+// AssertSameGoroutine panics if the calling goroutine is not the one that
+// created the checker. The value passed to `panic()` is an [*AssertionError]
+// whose message includes both IDs, e.g., `called from goroutine 7, expected
+// goroutine 1`.
+func (gc *GoroutineChecker) AssertSameGoroutine() {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if id := currentGoroutineID(); id != gc.id {
+		assertionFailed(fmt.Errorf("called from goroutine %d, expected goroutine %d", id, gc.id))
+	}
+}
+
+// currentGoroutineID returns the ID of the calling goroutine by parsing the
+// first line of its stack trace, which reads like "goroutine 7 [running]:".
+func currentGoroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if idx := bytes.IndexByte(buf, ' '); idx >= 0 {
+		buf = buf[:idx]
+	}
+	return PanicOnError1(strconv.ParseUint(string(buf), 10, 64))
+}
@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoroutineChecker(t *testing.T) {
+	t.Run("with the same goroutine does not panic", func(t *testing.T) {
+		gc := NewGoroutineChecker()
+		assert.NotPanics(t, func() {
+			gc.AssertSameGoroutine()
+		})
+	})
+
+	t.Run("with another goroutine panics", func(t *testing.T) {
+		gc := NewGoroutineChecker()
+		done := make(chan any)
+		go func() {
+			defer func() {
+				done <- recover()
+			}()
+			gc.AssertSameGoroutine()
+		}()
+		r := <-done
+		assert.True(t, IsAssertionError(r))
+		assert.Contains(t, r.(error).Error(), "called from goroutine ")
+	})
+}
+
+func TestCurrentGoroutineID(t *testing.T) {
+	id := currentGoroutineID()
+	assert.NotZero(t, id)
+	assert.Equal(t, id, currentGoroutineID())
+	other := make(chan uint64)
+	go func() {
+		other <- currentGoroutineID()
+	}()
+	assert.NotEqual(t, id, <-other)
+}
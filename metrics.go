@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"expvar"
+	"sync"
+	"sync/atomic"
+)
+
+// metricsEnabled is nonzero when metrics have been enabled using [SetMetricsEnabled].
+//
+// We use [atomic.LoadInt32] rather than an [atomic.Bool] because the inliner
+// charges less for the intrinsic, which keeps the PanicOnErrorN functions,
+// which check this variable when err is nil, cheap enough to be inlined.
+var metricsEnabled int32
+
+var (
+	// publishMetricsOnce ensures we publish the metrics at most once.
+	publishMetricsOnce sync.Once
+
+	// assertionFailures counts the assertion failures.
+	assertionFailures *expvar.Int
+
+	// panicOnErrorCalls counts the calls to the PanicOnErrorN family.
+	panicOnErrorCalls *expvar.Int
+
+	// panicOnErrorFailures counts the PanicOnErrorN calls with a non-nil error.
+	panicOnErrorFailures *expvar.Int
+)
+
+// SetMetricsEnabled enables or disables counting how often the package
+// helpers run and fail. The counters are published using [expvar] as:
+//
+//   - runtimex.assertions.failures: failed assertions;
+//
+//   - runtimex.panic_on_error.calls: calls to the PanicOnErrorN family;
+//
+//   - runtimex.panic_on_error.failures: PanicOnErrorN calls with a non-nil error.
+//
+// Metrics are disabled by default to avoid any overhead. The counters are
+// published the first time metrics are enabled, so importing this package
+// does not register [expvar] variables. Disabling metrics again stops
+// counting but does not unpublish or reset the counters. This function
+// is goroutine safe.
+//
+// The PanicOnErrorN family includes the helpers built on top of it (e.g.,
+// [Must] and [MustClose]), which count as calls as well. Helpers checking
+// an error per element, i.e., [MustMap], count a call per element.
+func SetMetricsEnabled(enabled bool) {
+	if enabled {
+		publishMetricsOnce.Do(publishMetrics)
+	}
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&metricsEnabled, value)
+}
+
+// publishMetrics creates and publishes the [expvar] counters.
+func publishMetrics() {
+	assertionFailures = expvar.NewInt("runtimex.assertions.failures")
+	panicOnErrorCalls = expvar.NewInt("runtimex.panic_on_error.calls")
+	panicOnErrorFailures = expvar.NewInt("runtimex.panic_on_error.failures")
+}
+
+// countAssertionFailure increments the failed assertions counter.
+func countAssertionFailure() {
+	if atomic.LoadInt32(&metricsEnabled) != 0 {
+		assertionFailures.Add(1)
+	}
+}
+
+// countPanicOnError increments the PanicOnErrorN counters.
+func countPanicOnError(err error) {
+	if atomic.LoadInt32(&metricsEnabled) != 0 {
+		panicOnErrorCalls.Add(1)
+		if err != nil {
+			panicOnErrorFailures.Add(1)
+		}
+	}
+}
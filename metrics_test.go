@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// expvarIntValue returns the value of the named [*expvar.Int].
+func expvarIntValue(t *testing.T, name string) int64 {
+	t.Helper()
+	v, ok := expvar.Get(name).(*expvar.Int)
+	if !ok {
+		t.Fatalf("expvar %s not published", name)
+	}
+	return v.Value()
+}
+
+func TestSetMetricsEnabled(t *testing.T) {
+	SetMetricsEnabled(true)
+	defer SetMetricsEnabled(false)
+
+	t.Run("counts assertion failures", func(t *testing.T) {
+		before := expvarIntValue(t, "runtimex.assertions.failures")
+		Assert(true)
+		assert.Panics(t, func() { Assert(false) })
+		assert.Panics(t, func() { AssertEqual(1, 2) })
+		assert.Equal(t, before+2, expvarIntValue(t, "runtimex.assertions.failures"))
+	})
+
+	t.Run("counts PanicOnErrorN calls and failures", func(t *testing.T) {
+		callsBefore := expvarIntValue(t, "runtimex.panic_on_error.calls")
+		failuresBefore := expvarIntValue(t, "runtimex.panic_on_error.failures")
+		PanicOnError0(nil)
+		PanicOnError1(17, nil)
+		assert.Panics(t, func() { PanicOnError2(17, 17, errors.New("test error")) })
+		assert.Equal(t, callsBefore+3, expvarIntValue(t, "runtimex.panic_on_error.calls"))
+		assert.Equal(t, failuresBefore+1, expvarIntValue(t, "runtimex.panic_on_error.failures"))
+	})
+
+	t.Run("counts PanicOnError1Context once per call", func(t *testing.T) {
+		callsBefore := expvarIntValue(t, "runtimex.panic_on_error.calls")
+		failuresBefore := expvarIntValue(t, "runtimex.panic_on_error.failures")
+		PanicOnError1Context(context.Background(), 17, nil)
+		assert.Panics(t, func() { PanicOnError1Context(canceledContext(), 17, nil) })
+		assert.Panics(t, func() { PanicOnError1Context(canceledContext(), 17, errors.New("test error")) })
+		assert.Equal(t, callsBefore+3, expvarIntValue(t, "runtimex.panic_on_error.calls"))
+		assert.Equal(t, failuresBefore+2, expvarIntValue(t, "runtimex.panic_on_error.failures"))
+	})
+
+	t.Run("counts PanicOnErrorAny calls and failures", func(t *testing.T) {
+		callsBefore := expvarIntValue(t, "runtimex.panic_on_error.calls")
+		failuresBefore := expvarIntValue(t, "runtimex.panic_on_error.failures")
+		PanicOnErrorAny(17, nil)
+		assert.Panics(t, func() { PanicOnErrorAny(17, errors.New("test error")) })
+		assert.Equal(t, callsBefore+2, expvarIntValue(t, "runtimex.panic_on_error.calls"))
+		assert.Equal(t, failuresBefore+1, expvarIntValue(t, "runtimex.panic_on_error.failures"))
+	})
+
+	t.Run("counts MustMap once per element", func(t *testing.T) {
+		callsBefore := expvarIntValue(t, "runtimex.panic_on_error.calls")
+		failuresBefore := expvarIntValue(t, "runtimex.panic_on_error.failures")
+		MustMap([]string{"1", "2", "3"}, strconv.Atoi)
+		assert.Panics(t, func() { MustMap([]string{"1", "x", "3"}, strconv.Atoi) })
+		assert.Equal(t, callsBefore+5, expvarIntValue(t, "runtimex.panic_on_error.calls"))
+		assert.Equal(t, failuresBefore+1, expvarIntValue(t, "runtimex.panic_on_error.failures"))
+	})
+
+	t.Run("does not count when disabled", func(t *testing.T) {
+		SetMetricsEnabled(false)
+		defer SetMetricsEnabled(true)
+		failuresBefore := expvarIntValue(t, "runtimex.assertions.failures")
+		callsBefore := expvarIntValue(t, "runtimex.panic_on_error.calls")
+		assert.Panics(t, func() { Assert(false) })
+		assert.Panics(t, func() { PanicOnError0(errors.New("test error")) })
+		assert.Equal(t, failuresBefore, expvarIntValue(t, "runtimex.assertions.failures"))
+		assert.Equal(t, callsBefore, expvarIntValue(t, "runtimex.panic_on_error.calls"))
+	})
+
+	t.Run("enabling again does not publish twice", func(t *testing.T) {
+		assert.NotPanics(t, func() { SetMetricsEnabled(true) })
+	})
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// exposeAssertionMessages is the flag configured using [SetExposeAssertionMessages].
+var exposeAssertionMessages atomic.Bool
+
+// SetExposeAssertionMessages controls whether [RecoverMiddleware] includes the
+// message of a failed assertion in the response body. This is useful during
+// development and is disabled by default to avoid leaking internal details
+// to clients. This function is goroutine safe.
+func SetExposeAssertionMessages(enabled bool) {
+	exposeAssertionMessages.Store(enabled)
+}
+
+// RecoverMiddleware returns an [http.Handler] that calls next and recovers
+// from panics occurring while serving a request, such that using
+// [PanicOnError1] and friends inside handlers does not crash the server.
+//
+// On panic, it logs the panic value using the [log] package and responds with
+// a 500 status code. The response body is the generic status text, except for
+// failed assertions when [SetExposeAssertionMessages] is enabled, in which case
+// the body contains the assertion message. Like [net/http], it does not recover
+// [http.ErrAbortHandler], which is used to abort a response.
+func RecoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if r == http.ErrAbortHandler {
+				panic(r)
+			}
+			err := NormalizeRecovered(r)
+			logPrint(fmt.Sprintf("runtimex: panic serving %s %s: %s", req.Method, req.URL.Path, err))
+			message := http.StatusText(http.StatusInternalServerError)
+			if IsAssertionError(r) && exposeAssertionMessages.Load() {
+				message = err.Error()
+			}
+			http.Error(w, message, http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(w, req)
+	})
+}
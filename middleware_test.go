@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoverMiddleware(t *testing.T) {
+	// Save original logPrint and restore after the test
+	originalLogPrint := logPrint
+	defer func() { logPrint = originalLogPrint }()
+
+	var logged []any
+	logPrint = func(v ...any) {
+		logged = append(logged, v...)
+	}
+
+	// serve runs handler through the middleware and returns the recorder.
+	serve := func(handler http.HandlerFunc) *httptest.ResponseRecorder {
+		logged = nil
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/path", nil)
+		RecoverMiddleware(handler).ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("without panic serves the response", func(t *testing.T) {
+		rr := serve(func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("ok"))
+		})
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "ok", rr.Body.String())
+		assert.Empty(t, logged)
+	})
+
+	t.Run("with error panic returns a generic 500", func(t *testing.T) {
+		rr := serve(func(w http.ResponseWriter, req *http.Request) {
+			PanicOnError1("value", errors.New("test error"))
+		})
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Equal(t, "Internal Server Error\n", rr.Body.String())
+		assert.Equal(t, []any{"runtimex: panic serving GET /path: test error"}, logged)
+	})
+
+	t.Run("with assertion panic returns a generic 500 by default", func(t *testing.T) {
+		rr := serve(func(w http.ResponseWriter, req *http.Request) {
+			AssertEqual(1, 2)
+		})
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Equal(t, "Internal Server Error\n", rr.Body.String())
+		assert.Len(t, logged, 1)
+	})
+
+	t.Run("with assertion panic exposes the message when enabled", func(t *testing.T) {
+		SetExposeAssertionMessages(true)
+		defer SetExposeAssertionMessages(false)
+		rr := serve(func(w http.ResponseWriter, req *http.Request) {
+			AssertEqual(1, 2)
+		})
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Equal(t, "expected equal, got 1 and 2\n", rr.Body.String())
+		assert.Len(t, logged, 1)
+	})
+
+	t.Run("with ErrAbortHandler re-panics", func(t *testing.T) {
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			serve(func(w http.ResponseWriter, req *http.Request) {
+				panic(http.ErrAbortHandler)
+			})
+		})
+	})
+}
@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Must is an alias for [PanicOnError1] named after the `Must` convention
+// used by the standard library (e.g., [regexp.MustCompile]). It panics if
+// err is not nil, like [PanicOnError1], and otherwise returns v.
+//
+// For example:
+//
+//	req := runtimex.Must(http.NewRequest("GET", URL, nil))
+func Must[T any](v T, err error) T {
+	panicOnError(1, err)
+	return v
+}
+
+// MustCompile is another alias for [PanicOnError1], meant for compiling
+// literal resources (e.g., regular expressions, templates) at initialization
+// time, where a failure is a programmer error. Functionally identical to
+// [Must], the distinct name documents the intent at the call site:
+//
+//	var re = runtimex.MustCompile(regexp.Compile(`^[a-z]+$`))
+func MustCompile[T any](v T, err error) T {
+	panicOnError(1, err)
+	return v
+}
+
+// MustClose closes c and passes the error returned by Close, if
+// any, to [PanicOnError0]. It is designed to be deferred:
+//
+//	defer runtimex.MustClose(fp)
+func MustClose(c io.Closer) {
+	panicOnError(1, c.Close())
+}
+
+// MustCloseAll closes all the closers, even when some of them fail, and
+// passes the errors returned by Close, if any, joined using [errors.Join],
+// to [PanicOnError0]. This ensures that an early failure does not cause
+// the following closers to leak. For example:
+//
+//	defer runtimex.MustCloseAll(db, cache, fp)
+func MustCloseAll(closers ...io.Closer) {
+	var errs []error
+	for _, c := range closers {
+		errs = append(errs, c.Close())
+	}
+	panicOnError(1, errors.Join(errs...))
+}
+
+// MustParseURL is like [url.Parse] but panics on failure. The value
+// passed to `panic()` wraps the parse error with context.
+//
+// Use this function for parsing literals that must be valid, e.g.:
+//
+//	var baseURL = runtimex.MustParseURL("https://example.com/")
+func MustParseURL(raw string) *url.URL {
+	u, err := url.Parse(raw)
+	panicOnErrorf(1, err, "cannot parse URL %q", raw)
+	return u
+}
+
+// MustParseTime is like [time.Parse] but panics on failure. The value
+// passed to `panic()` wraps the parse error with context.
+func MustParseTime(layout, value string) time.Time {
+	t, err := time.Parse(layout, value)
+	panicOnErrorf(1, err, "cannot parse time %q", value)
+	return t
+}
+
+// MustAtoi is like [strconv.Atoi] but panics on failure. The value
+// passed to `panic()` wraps the parse error with context.
+func MustAtoi(s string) int {
+	v, err := strconv.Atoi(s)
+	panicOnErrorf(1, err, "cannot parse integer %q", s)
+	return v
+}
+
+// MustMarshalJSON is like [json.Marshal] but panics on failure. The value
+// passed to `panic()` wraps the marshaling error with context. Use it for
+// values that can always be marshaled, e.g., structs without channels.
+func MustMarshalJSON(v any) []byte {
+	data, err := json.Marshal(v)
+	panicOnErrorf(1, err, "cannot marshal %T to JSON", v)
+	return data
+}
+
+// MustUnmarshalJSON is like [json.Unmarshal] but returns a new T and panics
+// on failure. The value passed to `panic()` wraps the unmarshaling error with
+// context. Use it for known-good data, e.g., string literals. For example:
+//
+//	cfg := runtimex.MustUnmarshalJSON[Config]([]byte(`{"port": 8080}`))
+func MustUnmarshalJSON[T any](data []byte) T {
+	var v T
+	err := json.Unmarshal(data, &v)
+	panicOnErrorf(1, err, "cannot unmarshal JSON into %v", reflect.TypeFor[T]())
+	return v
+}
+
+// MustCast converts v to T using a type assertion and panics on failure. The
+// value passed to `panic()` is an [*AssertionError] whose message includes
+// both types, e.g., `cannot cast int to string`, or `cannot cast nil to string`
+// when v is nil. Unlike a failed type assertion, the panic value is an error.
+func MustCast[T any](v any) T {
+	tv, ok := v.(T)
+	if !ok {
+		if v == nil {
+			panicOnError(1, fmt.Errorf("cannot cast nil to %v", reflect.TypeFor[T]()))
+		}
+		panicOnError(1, fmt.Errorf("cannot cast %T to %v", v, reflect.TypeFor[T]()))
+	}
+	return tv
+}
+
+// Deref returns the value pointed to by p and panics if p is nil. The value
+// passed to `panic()` is an [*AssertionError] whose message includes the pointer type,
+// e.g., `nil pointer dereference of *bytes.Buffer`. This is more legible
+// than the runtime error caused by dereferencing a nil pointer.
+func Deref[T any](p *T) T {
+	if p == nil {
+		panicOnError(1, fmt.Errorf("nil pointer dereference of %T", p))
+	}
+	return *p
+}
+
+// MustMap returns a slice containing the result of applying fn to each element
+// of in and panics on the first error. The value passed to `panic()` wraps the
+// error with the index of the failing element, e.g., `element 3: <err>`.
+// With [SetMetricsEnabled], each element counts as a PanicOnErrorN call.
+func MustMap[T, U any](in []T, fn func(T) (U, error)) []U {
+	out := make([]U, 0, len(in))
+	for idx, v := range in {
+		u, err := fn(v)
+		panicOnErrorf(1, err, "element %d", idx)
+		out = append(out, u)
+	}
+	return out
+}
+
+// MustLookup returns the value associated with key in m and panics if m does
+// not contain key. The value passed to `panic()` is an [*AssertionError]
+// whose message includes the key, e.g., `key foo not found`. A key mapping to the zero value
+// is found, since this function uses the comma-ok form of the lookup.
+func MustLookup[K comparable, V any](m map[K]V, key K) V {
+	v, found := m[key]
+	if !found {
+		panicOnError(1, fmt.Errorf("key %v not found", key))
+	}
+	return v
+}
+
+// Pair is a key-value pair used by [MapFromPairs].
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// MapFromPairs returns a map containing the given key-value pairs and panics
+// if a key appears more than once, which would otherwise silently overwrite
+// the previous value. The value passed to `panic()` is an [*AssertionError]
+// whose message includes the key, e.g., `duplicate key foo`. For example:
+//
+//	handlers := runtimex.MapFromPairs([]runtimex.Pair[string, http.Handler]{
+//		{"/", indexHandler},
+//		{"/health", healthHandler},
+//	})
+func MapFromPairs[K comparable, V any](pairs []Pair[K, V]) map[K]V {
+	m := make(map[K]V, len(pairs))
+	for _, p := range pairs {
+		if _, found := m[p.Key]; found {
+			panicOnError(1, fmt.Errorf("duplicate key %v", p.Key))
+		}
+		m[p.Key] = p.Value
+	}
+	return m
+}
+
+// MustLoad is like [MustLookup] but for a [*sync.Map].
+func MustLoad(m *sync.Map, key any) any {
+	v, found := m.Load(key)
+	if !found {
+		panicOnError(1, fmt.Errorf("key %v not found", key))
+	}
+	return v
+}
+
+// MustRetry calls fn up to attempts times and returns the value returned by
+// the first successful call. If all the calls fail, it panics with an
+// [*AssertionError] wrapping the last error along with the number of attempts,
+// e.g., `failed after 3 attempts: <err>`. Use it for operations that should not fail but may fail because of
+// transient conditions. The calls happen back to back, without any delay.
+//
+// It panics with an error if attempts is not positive, since that is a
+// programmer error.
+func MustRetry[T any](attempts int, fn func() (T, error)) T {
+	if attempts <= 0 {
+		panic(fmt.Errorf("MustRetry: attempts must be positive, got %d", attempts))
+	}
+	var (
+		v   T
+		err error
+	)
+	for range attempts {
+		if v, err = fn(); err == nil {
+			break
+		}
+	}
+	panicOnErrorf(1, err, "failed after %d attempts", attempts)
+	return v
+}
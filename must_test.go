@@ -0,0 +1,381 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMust(t *testing.T) {
+	t.Run("with nil error returns value", func(t *testing.T) {
+		assert.Equal(t, "x", Must("x", nil))
+	})
+
+	t.Run("with non-nil error panics", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		ae := recoverAssertionError(func() {
+			Must("x", expectedErr)
+		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+}
+
+func TestMustCompile(t *testing.T) {
+	t.Run("with nil error returns value", func(t *testing.T) {
+		re := MustCompile(regexp.Compile(`^[a-z]+$`))
+		assert.True(t, re.MatchString("abc"))
+	})
+
+	t.Run("with non-nil error panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "error parsing regexp: missing closing ]: `[`", func() {
+			MustCompile(regexp.Compile(`[`))
+		})
+	})
+}
+
+// fakeCloser is an [io.Closer] returning a configurable error.
+type fakeCloser struct {
+	closed bool
+	err    error
+}
+
+func (c *fakeCloser) Close() error {
+	c.closed = true
+	return c.err
+}
+
+func TestMustClose(t *testing.T) {
+	t.Run("with nil close error does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			MustClose(&fakeCloser{})
+		})
+	})
+
+	t.Run("with non-nil close error panics", func(t *testing.T) {
+		expectedErr := errors.New("close error")
+		ae := recoverAssertionError(func() {
+			MustClose(&fakeCloser{err: expectedErr})
+		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+}
+
+func TestMustCloseAll(t *testing.T) {
+	t.Run("with all closers succeeding does not panic", func(t *testing.T) {
+		c1, c2 := &fakeCloser{}, &fakeCloser{}
+		assert.NotPanics(t, func() {
+			MustCloseAll(c1, c2)
+		})
+		assert.True(t, c1.closed)
+		assert.True(t, c2.closed)
+	})
+
+	t.Run("with no closers does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			MustCloseAll()
+		})
+	})
+
+	t.Run("with one failing closer closes all and panics", func(t *testing.T) {
+		expectedErr := errors.New("close error")
+		c1, c2, c3 := &fakeCloser{}, &fakeCloser{err: expectedErr}, &fakeCloser{}
+		err := recoverError(func() {
+			MustCloseAll(c1, c2, c3)
+		})
+		assert.ErrorIs(t, err, expectedErr)
+		assert.True(t, c1.closed)
+		assert.True(t, c2.closed)
+		assert.True(t, c3.closed)
+	})
+
+	t.Run("with multiple failing closers panics with all the errors", func(t *testing.T) {
+		err1, err2 := errors.New("close error 1"), errors.New("close error 2")
+		c1, c2, c3 := &fakeCloser{err: err1}, &fakeCloser{}, &fakeCloser{err: err2}
+		err := recoverError(func() {
+			MustCloseAll(c1, c2, c3)
+		})
+		assert.ErrorIs(t, err, err1)
+		assert.ErrorIs(t, err, err2)
+		assert.EqualError(t, err, "close error 1\nclose error 2")
+		assert.True(t, c2.closed)
+		assert.True(t, c3.closed)
+	})
+}
+
+// recoverError calls fn and returns the error it panicked with, if any.
+func recoverError(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(error)
+		}
+	}()
+	fn()
+	return
+}
+
+func TestMustParseURL(t *testing.T) {
+	t.Run("with valid URL", func(t *testing.T) {
+		u := MustParseURL("https://example.com/path")
+		assert.Equal(t, "example.com", u.Host)
+		assert.Equal(t, "/path", u.Path)
+	})
+
+	t.Run("with invalid URL panics", func(t *testing.T) {
+		err := recoverError(func() {
+			MustParseURL("\x7f://")
+		})
+		var uerr *url.Error
+		assert.True(t, errors.As(err, &uerr))
+		assert.Contains(t, err.Error(), `cannot parse URL "\x7f://": `)
+	})
+}
+
+func TestMustParseTime(t *testing.T) {
+	t.Run("with valid time", func(t *testing.T) {
+		v := MustParseTime(time.RFC3339, "2024-01-01T00:00:00Z")
+		assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), v)
+	})
+
+	t.Run("with invalid time panics", func(t *testing.T) {
+		err := recoverError(func() {
+			MustParseTime(time.RFC3339, "yesterday")
+		})
+		var perr *time.ParseError
+		assert.True(t, errors.As(err, &perr))
+		assert.Contains(t, err.Error(), `cannot parse time "yesterday": `)
+	})
+}
+
+func TestMustAtoi(t *testing.T) {
+	t.Run("with valid integer", func(t *testing.T) {
+		assert.Equal(t, 17, MustAtoi("17"))
+	})
+
+	t.Run("with invalid integer panics", func(t *testing.T) {
+		err := recoverError(func() {
+			MustAtoi("seventeen")
+		})
+		assert.True(t, errors.Is(err, strconv.ErrSyntax))
+		assert.Contains(t, err.Error(), `cannot parse integer "seventeen": `)
+	})
+}
+
+func TestMustMarshalJSON(t *testing.T) {
+	t.Run("with marshalable struct returns the JSON", func(t *testing.T) {
+		data := MustMarshalJSON(comparableStruct{Name: "test", Value: 17})
+		assert.Equal(t, `{"Name":"test","Value":17}`, string(data))
+	})
+
+	t.Run("with unmarshalable value panics", func(t *testing.T) {
+		v := struct{ Ch chan int }{Ch: make(chan int)}
+		err := recoverError(func() {
+			MustMarshalJSON(v)
+		})
+		var typeErr *json.UnsupportedTypeError
+		assert.True(t, errors.As(err, &typeErr))
+		assert.Contains(t, err.Error(), "cannot marshal struct { Ch chan int } to JSON: ")
+	})
+}
+
+func TestMustUnmarshalJSON(t *testing.T) {
+	t.Run("with valid JSON returns the value", func(t *testing.T) {
+		v := MustUnmarshalJSON[comparableStruct]([]byte(`{"Name":"test","Value":17}`))
+		assert.Equal(t, comparableStruct{Name: "test", Value: 17}, v)
+	})
+
+	t.Run("with invalid JSON panics", func(t *testing.T) {
+		err := recoverError(func() {
+			MustUnmarshalJSON[comparableStruct]([]byte(`{`))
+		})
+		var syntaxErr *json.SyntaxError
+		assert.True(t, errors.As(err, &syntaxErr))
+		assert.Contains(t, err.Error(), "cannot unmarshal JSON into runtimex.comparableStruct: ")
+	})
+
+	t.Run("round trip preserves the value", func(t *testing.T) {
+		expected := comparableStruct{Name: "test", Value: 17}
+		assert.Equal(t, expected, MustUnmarshalJSON[comparableStruct](MustMarshalJSON(expected)))
+	})
+}
+
+func TestMustCast(t *testing.T) {
+	t.Run("with matching type returns the value", func(t *testing.T) {
+		assert.Equal(t, "value", MustCast[string]("value"))
+	})
+
+	t.Run("with matching interface returns the value", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		assert.Equal(t, expectedErr, MustCast[error](expectedErr))
+	})
+
+	t.Run("with wrong type panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "cannot cast int to string", func() {
+			MustCast[string](17)
+		})
+	})
+
+	t.Run("with nil panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "cannot cast nil to string", func() {
+			MustCast[string](nil)
+		})
+	})
+}
+
+func TestDeref(t *testing.T) {
+	t.Run("with valid pointer returns the value", func(t *testing.T) {
+		v := 17
+		assert.Equal(t, 17, Deref(&v))
+	})
+
+	t.Run("with nil pointer panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "nil pointer dereference of *url.URL", func() {
+			Deref((*url.URL)(nil))
+		})
+	})
+}
+
+func TestMustMap(t *testing.T) {
+	t.Run("with all successes returns the results", func(t *testing.T) {
+		assert.Equal(t, []int{1, 2, 3}, MustMap([]string{"1", "2", "3"}, strconv.Atoi))
+	})
+
+	t.Run("with empty input returns empty output", func(t *testing.T) {
+		out := MustMap([]string{}, strconv.Atoi)
+		assert.NotNil(t, out)
+		assert.Empty(t, out)
+	})
+
+	t.Run("with an error in the middle panics with the index", func(t *testing.T) {
+		var calls int
+		err := recoverError(func() {
+			MustMap([]string{"1", "x", "3"}, func(s string) (int, error) {
+				calls++
+				return strconv.Atoi(s)
+			})
+		})
+		assert.Equal(t, 2, calls)
+		assert.True(t, errors.Is(err, strconv.ErrSyntax))
+		assert.EqualError(t, err, `element 1: strconv.Atoi: parsing "x": invalid syntax`)
+	})
+}
+
+func TestMustLookup(t *testing.T) {
+	m := map[string]int{"a": 1, "zero": 0}
+
+	t.Run("with present key returns the value", func(t *testing.T) {
+		assert.Equal(t, 1, MustLookup(m, "a"))
+	})
+
+	t.Run("with present key mapping to the zero value returns it", func(t *testing.T) {
+		assert.Equal(t, 0, MustLookup(m, "zero"))
+	})
+
+	t.Run("with absent key panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "key b not found", func() {
+			MustLookup(m, "b")
+		})
+	})
+
+	t.Run("with nil map panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "key b not found", func() {
+			MustLookup(map[string]int(nil), "b")
+		})
+	})
+}
+
+func TestMapFromPairs(t *testing.T) {
+	t.Run("with unique keys returns the map", func(t *testing.T) {
+		m := MapFromPairs([]Pair[string, int]{{"a", 1}, {"b", 2}})
+		assert.Equal(t, map[string]int{"a": 1, "b": 2}, m)
+	})
+
+	t.Run("with a duplicate key panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "duplicate key a", func() {
+			MapFromPairs([]Pair[string, int]{{"a", 1}, {"b", 2}, {"a", 3}})
+		})
+	})
+
+	t.Run("with empty input returns an empty map", func(t *testing.T) {
+		m := MapFromPairs[string, int](nil)
+		assert.NotNil(t, m)
+		assert.Empty(t, m)
+	})
+}
+
+func TestMustLoad(t *testing.T) {
+	var m sync.Map
+	m.Store("a", 1)
+	m.Store("nil", nil)
+
+	t.Run("with present key returns the value", func(t *testing.T) {
+		assert.Equal(t, 1, MustLoad(&m, "a"))
+	})
+
+	t.Run("with present key mapping to nil returns nil", func(t *testing.T) {
+		assert.Nil(t, MustLoad(&m, "nil"))
+	})
+
+	t.Run("with absent key panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "key b not found", func() {
+			MustLoad(&m, "b")
+		})
+	})
+}
+
+func TestMustRetry(t *testing.T) {
+	t.Run("with success on the first attempt returns the value", func(t *testing.T) {
+		var calls int
+		v := MustRetry(3, func() (string, error) {
+			calls++
+			return "value", nil
+		})
+		assert.Equal(t, "value", v)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("with success after a transient failure returns the value", func(t *testing.T) {
+		var calls int
+		v := MustRetry(3, func() (string, error) {
+			calls++
+			if calls < 2 {
+				return "", errors.New("transient error")
+			}
+			return "value", nil
+		})
+		assert.Equal(t, "value", v)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("with all attempts failing panics with the last error", func(t *testing.T) {
+		var calls int
+		expectedErr := errors.New("last error")
+		err := recoverError(func() {
+			MustRetry(3, func() (string, error) {
+				calls++
+				if calls < 3 {
+					return "", errors.New("transient error")
+				}
+				return "", expectedErr
+			})
+		})
+		assert.EqualError(t, err, "failed after 3 attempts: last error")
+		assert.ErrorIs(t, err, expectedErr)
+		assert.True(t, IsAssertionError(err))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("with non-positive attempts panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "MustRetry: attempts must be positive, got 0", func() {
+			MustRetry(0, func() (string, error) { return "value", nil })
+		})
+	})
+}
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+// Package mustio contains file I/O helpers that panic on failure.
+//
+// They are meant for loading test fixtures and for small tools where
+// failing to read or write a file is an unrecoverable condition. Each
+// panic value wraps the underlying error with the operation and the
+// path, such that the panic message is actionable and [errors.Is]
+// still matches the original error (e.g., [fs.ErrNotExist]).
+package mustio
+
+import (
+	"io/fs"
+	"os"
+
+	"github.com/bassosimone/runtimex"
+)
+
+// MustReadFile is like [os.ReadFile] but panics on failure.
+func MustReadFile(path string) []byte {
+	data, err := os.ReadFile(path)
+	runtimex.PanicOnError0fDepth(1, err, "mustio: cannot read %s", path)
+	return data
+}
+
+// MustOpen is like [os.Open] but panics on failure.
+func MustOpen(path string) *os.File {
+	fp, err := os.Open(path)
+	runtimex.PanicOnError0fDepth(1, err, "mustio: cannot open %s", path)
+	return fp
+}
+
+// MustWriteFile is like [os.WriteFile] but panics on failure.
+func MustWriteFile(path string, data []byte, perm fs.FileMode) {
+	runtimex.PanicOnError0fDepth(1, os.WriteFile(path, data, perm), "mustio: cannot write %s", path)
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package mustio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/bassosimone/runtimex"
+	"github.com/stretchr/testify/assert"
+)
+
+// recoverError calls fn and returns the error it panicked with, if any.
+func recoverError(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(error)
+		}
+	}()
+	fn()
+	return
+}
+
+func TestMustWriteFileAndMustReadFile(t *testing.T) {
+	t.Run("with valid path", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "file.txt")
+		MustWriteFile(path, []byte("hello"), 0600)
+		assert.Equal(t, []byte("hello"), MustReadFile(path))
+	})
+
+	t.Run("MustReadFile with nonexistent path panics", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nonexistent.txt")
+		err := recoverError(func() {
+			MustReadFile(path)
+		})
+		assert.True(t, errors.Is(err, fs.ErrNotExist))
+		assert.True(t, strings.HasPrefix(err.Error(), "mustio: cannot read "+path+": "))
+	})
+
+	t.Run("MustWriteFile with nonexistent dir panics", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nonexistent", "file.txt")
+		err := recoverError(func() {
+			MustWriteFile(path, []byte("hello"), 0600)
+		})
+		assert.True(t, errors.Is(err, fs.ErrNotExist))
+		assert.True(t, strings.HasPrefix(err.Error(), "mustio: cannot write "+path+": "))
+	})
+}
+
+func TestMustOpen(t *testing.T) {
+	t.Run("with valid path", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "file.txt")
+		MustWriteFile(path, []byte("hello"), 0600)
+		fp := MustOpen(path)
+		defer fp.Close()
+		data, err := io.ReadAll(fp)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hello"), data)
+	})
+
+	t.Run("with nonexistent path panics", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nonexistent.txt")
+		err := recoverError(func() {
+			MustOpen(path)
+		})
+		assert.True(t, errors.Is(err, fs.ErrNotExist))
+		assert.True(t, strings.HasPrefix(err.Error(), "mustio: cannot open "+path+": "))
+	})
+}
+
+func TestIncludeCaller(t *testing.T) {
+	// Make sure we restore the default after the test
+	defer runtimex.SetIncludeCaller(false)
+	runtimex.SetIncludeCaller(true)
+
+	path := filepath.Join(t.TempDir(), "nonexistent.txt")
+	var line int
+	err := recoverError(func() {
+		_, _, line, _ = runtime.Caller(0)
+		MustReadFile(path) // must be on the line following runtime.Caller
+	})
+	expected := fmt.Sprintf("mustio_test.go:%d: mustio: cannot read %s: ", line+1, path)
+	assert.True(t, strings.HasPrefix(err.Error(), expected), err.Error())
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+}
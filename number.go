@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"fmt"
+	"math"
+)
+
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Float is a constraint that permits any floating-point type.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	Integer | Float
+}
+
+// AssertPositive panics unless v is greater than zero. The value passed
+// to `panic()` is an [*AssertionError] whose message includes v, e.g.,
+// `expected positive value, got -1`. A NaN value is not positive.
+func AssertPositive[T Number](v T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !(v > 0) {
+		assertionFailed(fmt.Errorf("expected positive value, got %v", v))
+	}
+}
+
+// AssertNonNegative panics unless v is greater than or equal to zero. The
+// value passed to `panic()` is an [*AssertionError] whose message includes
+// v, e.g., `expected non-negative value, got -1`. A NaN value is not
+// non-negative.
+func AssertNonNegative[T Number](v T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !(v >= 0) {
+		assertionFailed(fmt.Errorf("expected non-negative value, got %v", v))
+	}
+}
+
+// AssertInRange panics unless lo <= v <= hi. The value passed to `panic()`
+// is an [*AssertionError] whose message includes v and the range, e.g.,
+// `value 7 not in range [0, 5]`. A NaN value is never in range.
+func AssertInRange[T Number](v, lo, hi T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !(v >= lo && v <= hi) {
+		assertionFailed(fmt.Errorf("value %v not in range [%v, %v]", v, lo, hi))
+	}
+}
+
+// AssertFinite panics if v is NaN or infinite. The value passed to `panic()`
+// is an [*AssertionError] whose message includes v, i.e., `expected finite
+// value, got NaN`, `expected finite value, got +Inf`, or `expected finite
+// value, got -Inf`.
+func AssertFinite[T Float](v T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if f := float64(v); math.IsNaN(f) || math.IsInf(f, 0) {
+		assertionFailed(fmt.Errorf("expected finite value, got %v", f))
+	}
+}
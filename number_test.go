@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertPositive(t *testing.T) {
+	t.Run("with positive values does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertPositive(1)
+			AssertPositive(uint8(1))
+			AssertPositive(0.1)
+		})
+	})
+
+	t.Run("with zero panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected positive value, got 0", func() {
+			AssertPositive(0)
+		})
+	})
+
+	t.Run("with negative values panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected positive value, got -1", func() {
+			AssertPositive(-1)
+		})
+		assert.PanicsWithError(t, "expected positive value, got -0.5", func() {
+			AssertPositive(-0.5)
+		})
+	})
+
+	t.Run("with NaN panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected positive value, got NaN", func() {
+			AssertPositive(math.NaN())
+		})
+	})
+}
+
+func TestAssertNonNegative(t *testing.T) {
+	t.Run("with non-negative values does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertNonNegative(0)
+			AssertNonNegative(1)
+			AssertNonNegative(0.0)
+		})
+	})
+
+	t.Run("with negative values panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected non-negative value, got -1", func() {
+			AssertNonNegative(-1)
+		})
+		assert.PanicsWithError(t, "expected non-negative value, got -0.5", func() {
+			AssertNonNegative(-0.5)
+		})
+	})
+
+	t.Run("with NaN panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected non-negative value, got NaN", func() {
+			AssertNonNegative(math.NaN())
+		})
+	})
+}
+
+func TestAssertInRange(t *testing.T) {
+	t.Run("with values in range does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertInRange(0, 0, 5)
+			AssertInRange(3, 0, 5)
+			AssertInRange(5, 0, 5)
+			AssertInRange(-1, -1, 1)
+			AssertInRange(0.5, 0.5, 1.5)
+			AssertInRange(1.5, 0.5, 1.5)
+		})
+	})
+
+	t.Run("with values out of range panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "value 7 not in range [0, 5]", func() {
+			AssertInRange(7, 0, 5)
+		})
+		assert.PanicsWithError(t, "value -1 not in range [0, 5]", func() {
+			AssertInRange(-1, 0, 5)
+		})
+		assert.PanicsWithError(t, "value 1.6 not in range [0.5, 1.5]", func() {
+			AssertInRange(1.6, 0.5, 1.5)
+		})
+	})
+
+	t.Run("with NaN panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "value NaN not in range [0, 5]", func() {
+			AssertInRange(math.NaN(), 0, 5)
+		})
+	})
+}
+
+func TestAssertFinite(t *testing.T) {
+	t.Run("with finite values does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertFinite(0.0)
+			AssertFinite(-1.5)
+			AssertFinite(float32(math.MaxFloat32))
+		})
+	})
+
+	t.Run("with NaN panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected finite value, got NaN", func() {
+			AssertFinite(math.NaN())
+		})
+	})
+
+	t.Run("with +Inf panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected finite value, got +Inf", func() {
+			AssertFinite(math.Inf(1))
+		})
+	})
+
+	t.Run("with -Inf panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected finite value, got -Inf", func() {
+			AssertFinite(float32(math.Inf(-1)))
+		})
+	})
+}
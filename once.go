@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Once asserts that a code path runs at most once. Unlike [sync.Once], which
+// silently ignores subsequent calls, Once treats them as a programmer error:
+//
+//	var initOnce runtimex.Once
+//
+//	func initialize() {
+//		initOnce.Do()
+//		// ...
+//	}
+//
+// The zero value is ready to use. Once is goroutine safe.
+type Once struct {
+	called atomic.Bool
+}
+
+// Do panics if it has already been called. The value passed to `panic()`
+// is an [*AssertionError] whose message is `function called more than once`.
+func (o *Once) Do() {
+	if !o.called.CompareAndSwap(false, true) && assertionsEnabled() {
+		assertionFailed(errors.New("function called more than once"))
+	}
+}
+
+// MustInit returns a getter that calls init the first time it is invoked and
+// returns the value returned by init on every invocation. If init fails, the
+// getter panics, on every invocation, with an error wrapping the init error,
+// e.g., `cannot initialize *sql.DB: <err>`. This is better than a nil
+// dereference happening far from the failure. For example:
+//
+//	var getDB = runtimex.MustInit(func() (*sql.DB, error) {
+//		return sql.Open("sqlite", "app.db")
+//	})
+//
+// The getter is goroutine safe and init runs at most once.
+func MustInit[T any](init func() (T, error)) func() T {
+	get := sync.OnceValues(init)
+	return func() T {
+		v, err := get()
+		panicOnErrorf(1, err, "cannot initialize %v", reflect.TypeFor[T]())
+		return v
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnce(t *testing.T) {
+	t.Run("a single call does not panic", func(t *testing.T) {
+		var once Once
+		assert.NotPanics(t, func() {
+			once.Do()
+		})
+	})
+
+	t.Run("a second call panics", func(t *testing.T) {
+		var once Once
+		once.Do()
+		assert.PanicsWithError(t, "function called more than once", func() {
+			once.Do()
+		})
+	})
+
+	t.Run("concurrent calls panic all but once", func(t *testing.T) {
+		const count = 16
+		var (
+			once   Once
+			panics atomic.Int64
+			wg     sync.WaitGroup
+		)
+		for range count {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() {
+					if recover() != nil {
+						panics.Add(1)
+					}
+				}()
+				once.Do()
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int64(count-1), panics.Load())
+	})
+}
+
+func TestMustInit(t *testing.T) {
+	t.Run("init runs once across concurrent callers", func(t *testing.T) {
+		var calls atomic.Int64
+		get := MustInit(func() (*comparableStruct, error) {
+			calls.Add(1)
+			return &comparableStruct{Name: "test", Value: 17}, nil
+		})
+		var wg sync.WaitGroup
+		results := make([]*comparableStruct, 16)
+		for idx := range results {
+			wg.Go(func() {
+				results[idx] = get()
+			})
+		}
+		wg.Wait()
+		assert.Equal(t, int64(1), calls.Load())
+		for _, v := range results {
+			assert.Same(t, results[0], v)
+		}
+	})
+
+	t.Run("init error panics on every access", func(t *testing.T) {
+		var calls int
+		expectedErr := errors.New("test error")
+		get := MustInit(func() (int, error) {
+			calls++
+			return 0, expectedErr
+		})
+		for range 2 {
+			err := recoverError(func() {
+				get()
+			})
+			assert.EqualError(t, err, "cannot initialize int: test error")
+			assert.ErrorIs(t, err, expectedErr)
+		}
+		assert.Equal(t, 1, calls)
+	})
+}
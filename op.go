@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+// PanicOnError0Op is like [PanicOnError0] but the [*AssertionError] passed to
+// `panic()` wraps err with the name of the operation that failed, such that
+// the message is "<op>: <err>" and [errors.Is] still matches err. For example:
+//
+//	runtimex.PanicOnError0Op("load config", cfg.Validate())
+func PanicOnError0Op(op string, err error) {
+	panicOnErrorf(1, err, "%s", op)
+}
+
+// PanicOnError1Op is like [PanicOnError1] but wraps err with op like [PanicOnError0Op].
+func PanicOnError1Op[T1 any](op string, v1 T1, err error) T1 {
+	panicOnErrorf(1, err, "%s", op)
+	return v1
+}
+
+// PanicOnError2Op is like [PanicOnError2] but wraps err with op like [PanicOnError0Op].
+func PanicOnError2Op[T1, T2 any](op string, v1 T1, v2 T2, err error) (T1, T2) {
+	panicOnErrorf(1, err, "%s", op)
+	return v1, v2
+}
+
+// PanicOnError3Op is like [PanicOnError3] but wraps err with op like [PanicOnError0Op].
+func PanicOnError3Op[T1, T2, T3 any](op string, v1 T1, v2 T2, v3 T3, err error) (T1, T2, T3) {
+	panicOnErrorf(1, err, "%s", op)
+	return v1, v2, v3
+}
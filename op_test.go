@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recoverWrappedError calls fn and checks that it panics with an error
+// whose message is expectedMessage and that wraps expectedErr.
+func recoverWrappedError(t *testing.T, expectedErr error, expectedMessage string, fn func()) {
+	defer func() {
+		err := recover().(error)
+		assert.EqualError(t, err, expectedMessage)
+		assert.True(t, errors.Is(err, expectedErr))
+	}()
+	fn()
+}
+
+func TestPanicOnErrorOp(t *testing.T) {
+	expectedErr := errors.New("test error")
+
+	t.Run("PanicOnError0Op", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			PanicOnError0Op("op0", nil)
+		})
+		recoverWrappedError(t, expectedErr, "op0: test error", func() {
+			PanicOnError0Op("op0", expectedErr)
+		})
+	})
+
+	t.Run("PanicOnError1Op", func(t *testing.T) {
+		assert.Equal(t, "a", PanicOnError1Op("op1", "a", nil))
+		recoverWrappedError(t, expectedErr, "op1: test error", func() {
+			PanicOnError1Op("op1", "a", expectedErr)
+		})
+	})
+
+	t.Run("PanicOnError2Op", func(t *testing.T) {
+		v1, v2 := PanicOnError2Op("op2", "a", 1, nil)
+		assert.Equal(t, "a", v1)
+		assert.Equal(t, 1, v2)
+		recoverWrappedError(t, expectedErr, "op2: test error", func() {
+			PanicOnError2Op("op2", "a", 1, expectedErr)
+		})
+	})
+
+	t.Run("PanicOnError3Op", func(t *testing.T) {
+		v1, v2, v3 := PanicOnError3Op("op3", "a", 1, true, nil)
+		assert.Equal(t, "a", v1)
+		assert.Equal(t, 1, v2)
+		assert.Equal(t, true, v3)
+		recoverWrappedError(t, expectedErr, "op3: test error", func() {
+			PanicOnError3Op("op3", "a", 1, true, expectedErr)
+		})
+	})
+}
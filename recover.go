@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// CatchPanic calls fn and returns the value passed to `panic()` converted to
+// an error using [NormalizeRecovered], or nil if fn returned normally. When
+// stack capture is enabled using [SetCaptureStack], the error is wrapped by
+// a [*RecoveredError] containing the stack of the panic.
+//
+// You typically use this function at the boundary of a package that uses
+// [PanicOnError1] and friends internally but exposes a regular error API:
+//
+//	func Load(path string) (cfg *Config, err error) {
+//		err = runtimex.CatchPanic(func() {
+//			data := runtimex.PanicOnError1(os.ReadFile(path))
+//			cfg = runtimex.PanicOnError1(parseConfig(data))
+//		})
+//		return
+//	}
+func CatchPanic(fn func()) (err error) {
+	defer func() {
+		err = newRecoveredError(recover())
+	}()
+	fn()
+	return
+}
+
+// RecoveredError is the error returned by [CatchPanic] and [WithRecover] when
+// stack capture is enabled using [SetCaptureStack]. It contains the stack of
+// the panic, which is otherwise lost when converting a panic to an error.
+type RecoveredError struct {
+	// Err is the panic value converted using [NormalizeRecovered].
+	Err error
+
+	// stack contains the program counters captured while recovering.
+	stack []uintptr
+}
+
+var _ error = &RecoveredError{}
+
+// Error implements error.
+func (e *RecoveredError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *RecoveredError) Unwrap() error {
+	return e.Err
+}
+
+// StackTrace returns the program counters of the stack frames leading to the
+// panic, starting from the function that panicked.
+func (e *RecoveredError) StackTrace() []uintptr {
+	return e.stack
+}
+
+// Stack returns a human readable representation of [*RecoveredError.StackTrace]
+// formatted like [*AssertionError.Stack].
+func (e *RecoveredError) Stack() string {
+	return formatStack(e.stack)
+}
+
+// recoveredErrorSkip is the number of stack frames to skip with [runtime.Callers]
+// such that the captured stack starts at the function that panicked. We skip
+// [runtime.Callers], [newRecoveredError], the deferred function, and the
+// runtime function implementing `panic()`.
+const recoveredErrorSkip = 4
+
+// newRecoveredError converts r using [NormalizeRecovered] and, when stack capture
+// is enabled, wraps the result using a [*RecoveredError]. This function must be
+// called directly by the deferred function calling `recover()`, while the stack
+// of the panic is still available, for [recoveredErrorSkip] to be correct.
+func newRecoveredError(r any) error {
+	err := NormalizeRecovered(r)
+	if err == nil || !captureStack.Load() {
+		return err
+	}
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(recoveredErrorSkip, pcs)
+	return &RecoveredError{Err: err, stack: pcs[:n]}
+}
+
+// WithRecover is like [CatchPanic] but for functions returning a value. It
+// returns the value returned by fn, or the zero value of T and the panic
+// value as an error if fn panics. For example:
+//
+//	v, err := runtimex.WithRecover(func() int {
+//		return runtimex.PanicOnError1(strconv.Atoi(s))
+//	})
+func WithRecover[T any](fn func() T) (result T, err error) {
+	err = CatchPanic(func() {
+		result = fn()
+	})
+	return
+}
+
+// RecoverAndExit recovers from a panic and, if there was one, logs the
+// panic value and exits like [LogFatalOnError0]. Otherwise, it does nothing.
+//
+// You typically defer this function at the top of main() such that failed
+// assertions terminate the program with a log message rather than with the
+// goroutine stack traces printed by the Go runtime:
+//
+//	func main() {
+//		defer runtimex.RecoverAndExit()
+//		// ...
+//	}
+//
+// This function must be deferred directly, otherwise `recover()` returns nil.
+func RecoverAndExit() {
+	if err := NormalizeRecovered(recover()); err != nil {
+		logFatalError(err)
+	}
+}
+
+// SafeGo runs fn in a background goroutine and recovers from any panic
+// occurring inside fn, passing the panic value to onError as an error
+// converted using [NormalizeRecovered].
+//
+// If fn returns normally, onError is not called.
+//
+// Use this function instead of a bare `go` statement when fn uses
+// [PanicOnError1] and friends and a failure should not crash the program.
+func SafeGo(fn func(), onError func(error)) {
+	go func() {
+		defer func() {
+			if err := NormalizeRecovered(recover()); err != nil {
+				onError(err)
+			}
+		}()
+		fn()
+	}()
+}
+
+// GuardBoundary calls fn and, if fn panics, passes the panic value converted
+// to an error using [NormalizeRecovered] to logger. Then, if rethrow is true,
+// it re-panics with the original panic value. Otherwise, it returns normally.
+//
+// Use it at the public API boundary of a library using this package internally
+// to apply a uniform policy, e.g., logging in production and also crashing in
+// debug builds:
+//
+//	func (c *Client) Do() {
+//		runtimex.GuardBoundary(c.logError, c.debug, func() {
+//			// ...
+//		})
+//	}
+func GuardBoundary(logger func(error), rethrow bool, fn func()) {
+	r := callAndRecover(fn)
+	if err := NormalizeRecovered(r); err != nil {
+		logger(err)
+		if rethrow {
+			panic(r)
+		}
+	}
+}
+
+// RecoverToError recovers from a panic and, if there was one, assigns the
+// panic value to *errp converted to an error using [NormalizeRecovered].
+// Without a panic, *errp is left untouched.
+//
+// This function must be deferred directly, with errp pointing to a
+// named return value, otherwise `recover()` returns nil:
+//
+//	func Load(path string) (cfg *Config, err error) {
+//		defer runtimex.RecoverToError(&err)
+//		data := runtimex.PanicOnError1(os.ReadFile(path))
+//		return runtimex.PanicOnError1(parseConfig(data)), nil
+//	}
+func RecoverToError(errp *error) {
+	if err := NormalizeRecovered(recover()); err != nil {
+		*errp = err
+	}
+}
+
+// ForEachSafe calls fn on each element of items, recovering from panics such
+// that a failing element does not prevent processing the following ones. It
+// returns the panic values converted to errors using [NormalizeRecovered] and
+// wrapped with the index of the failing element, e.g., `element 3: <err>`,
+// or nil if no call panicked. For example:
+//
+//	errs := runtimex.ForEachSafe(paths, func(path string) {
+//		data := runtimex.PanicOnError1(os.ReadFile(path))
+//		process(data)
+//	})
+func ForEachSafe[T any](items []T, fn func(T)) (errs []error) {
+	for idx, item := range items {
+		r := callAndRecover(func() {
+			fn(item)
+		})
+		if err := NormalizeRecovered(r); err != nil {
+			errs = append(errs, fmt.Errorf("element %d: %w", idx, err))
+		}
+	}
+	return
+}
+
+// NormalizeRecovered converts r, typically the value returned by `recover()`,
+// to an error. It returns nil if r is nil. If r is an [*AssertionError], it
+// returns the underlying error. If r is another error, it returns r, including
+// when r wraps an [*AssertionError], such that the outer context is preserved.
+// Otherwise, it wraps r using `fmt.Errorf("panic: %v")`. For example:
+//
+//	defer func() {
+//		if err := runtimex.NormalizeRecovered(recover()); err != nil {
+//			log.Printf("recovered: %s", err)
+//		}
+//	}()
+func NormalizeRecovered(r any) error {
+	if r == nil {
+		return nil
+	}
+	if ae, ok := r.(*AssertionError); ok {
+		return ae.Err
+	}
+	if err, ok := r.(error); ok {
+		return err
+	}
+	return fmt.Errorf("panic: %v", r)
+}
+
+// HandleRecovered passes r, typically the value returned by `recover()`, to
+// handler converted to an error using [NormalizeRecovered] if r originates
+// from this package, i.e., if [IsAssertionError] returns true, which includes
+// the errors passed to `panic()` by the PanicOnErrorN family. If r is nil, it
+// does nothing.
+//
+// Otherwise, it re-panics with r, since a [runtime.Error] (e.g., an index out
+// of range or a write to a nil map), an error passed to `panic()` by other
+// code, or a non-error value indicates a different kind of bug that should
+// not be silently handled. For example:
+//
+//	defer func() {
+//		runtimex.HandleRecovered(recover(), func(err error) {
+//			log.Printf("invariant violation: %s", err)
+//		})
+//	}()
+func HandleRecovered(r any, handler func(err error)) {
+	if r == nil {
+		return
+	}
+	if !IsAssertionError(r) {
+		panic(r)
+	}
+	handler(NormalizeRecovered(r))
+}
+
+// AssertNoPanic calls fn and panics if fn panics. The value passed to `panic()`
+// is an [*AssertionError] wrapping the original panic value, with a message
+// like `unexpected panic: <value>`, which attributes the failure clearly.
+func AssertNoPanic(fn func()) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		fn()
+		return
+	}
+	if r := callAndRecover(fn); r != nil {
+		if err, ok := r.(error); ok {
+			assertionFailed(fmt.Errorf("unexpected panic: %w", err))
+			return
+		}
+		assertionFailed(fmt.Errorf("unexpected panic: %v", r))
+	}
+}
+
+// AssertPanics calls fn and panics if fn does not panic. Otherwise, it returns
+// the value passed to `panic()` by fn, for further inspection. When fn does not
+// panic, the value passed to `panic()` is an [*AssertionError] whose message is
+// `expected panic`.
+func AssertPanics(fn func()) (recovered any) {
+	recovered = callAndRecover(fn)
+	if recovered == nil && assertionsEnabled() {
+		assertionFailed(errors.New("expected panic"))
+	}
+	return
+}
+
+// callAndRecover calls fn and returns the value returned by `recover()`.
+func callAndRecover(fn func()) (r any) {
+	defer func() {
+		r = recover()
+	}()
+	fn()
+	return
+}
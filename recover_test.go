@@ -0,0 +1,483 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatchPanic(t *testing.T) {
+	t.Run("without panic returns nil", func(t *testing.T) {
+		called := false
+		err := CatchPanic(func() {
+			called = true
+		})
+		assert.NoError(t, err)
+		assert.True(t, called)
+	})
+
+	t.Run("with error panic returns the error", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		err := CatchPanic(func() {
+			PanicOnError1("value", expectedErr)
+		})
+		assert.Equal(t, expectedErr, err)
+	})
+
+	t.Run("with assertion panic returns the underlying error", func(t *testing.T) {
+		err := CatchPanic(func() {
+			Assert(false)
+		})
+		assert.False(t, IsAssertionError(err))
+		assert.EqualError(t, err, "assertion failed")
+	})
+
+	t.Run("with string panic returns a wrapped error", func(t *testing.T) {
+		err := CatchPanic(func() {
+			panic("test value")
+		})
+		assert.EqualError(t, err, "panic: test value")
+	})
+}
+
+func TestRecoveredError(t *testing.T) {
+	// Make sure we restore the default after the test
+	defer SetCaptureStack(false)
+
+	t.Run("when stack capture is enabled the error contains the stack", func(t *testing.T) {
+		SetCaptureStack(true)
+		expectedErr := errors.New("test error")
+		err := CatchPanic(func() {
+			panic(expectedErr)
+		})
+		var re *RecoveredError
+		if !assert.True(t, errors.As(err, &re)) {
+			return
+		}
+		assert.Equal(t, expectedErr, errors.Unwrap(err))
+		assert.EqualError(t, err, "test error")
+		assert.NotEmpty(t, re.StackTrace())
+		frame, _ := runtime.CallersFrames(re.StackTrace()).Next()
+		assert.Equal(t, "github.com/bassosimone/runtimex.TestRecoveredError.func1.1", frame.Function)
+		assert.Contains(t, re.Stack(), "recover_test.go")
+	})
+
+	t.Run("when stack capture is enabled WithRecover also returns the stack", func(t *testing.T) {
+		SetCaptureStack(true)
+		_, err := WithRecover(func() int {
+			panic("test value")
+		})
+		var re *RecoveredError
+		if assert.True(t, errors.As(err, &re)) {
+			assert.NotEmpty(t, re.Stack())
+			assert.EqualError(t, errors.Unwrap(err), "panic: test value")
+		}
+	})
+
+	t.Run("when stack capture is disabled the error is not wrapped", func(t *testing.T) {
+		SetCaptureStack(false)
+		expectedErr := errors.New("test error")
+		err := CatchPanic(func() {
+			PanicOnError0(expectedErr)
+		})
+		assert.Equal(t, expectedErr, err)
+	})
+}
+
+func TestWithRecover(t *testing.T) {
+	t.Run("without panic returns the value", func(t *testing.T) {
+		v, err := WithRecover(func() string {
+			return "value"
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "value", v)
+	})
+
+	t.Run("with error panic returns zero value and the error", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		v, err := WithRecover(func() string {
+			return PanicOnError1("value", expectedErr)
+		})
+		assert.Equal(t, expectedErr, err)
+		assert.Equal(t, "", v)
+	})
+
+	t.Run("with string panic returns zero value and a wrapped error", func(t *testing.T) {
+		v, err := WithRecover(func() int {
+			panic("test value")
+		})
+		assert.EqualError(t, err, "panic: test value")
+		assert.Equal(t, 0, v)
+	})
+}
+
+func TestForEachSafe(t *testing.T) {
+	t.Run("without panics returns nil", func(t *testing.T) {
+		var visited []int
+		errs := ForEachSafe([]int{1, 2, 3}, func(v int) {
+			visited = append(visited, v)
+		})
+		assert.Nil(t, errs)
+		assert.Equal(t, []int{1, 2, 3}, visited)
+	})
+
+	t.Run("with some panics collects errors with indices", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		var visited []int
+		errs := ForEachSafe([]int{0, 1, 2, 3}, func(v int) {
+			visited = append(visited, v)
+			switch v {
+			case 1:
+				PanicOnError0(expectedErr)
+			case 3:
+				Assert(false)
+			}
+		})
+		assert.Equal(t, []int{0, 1, 2, 3}, visited)
+		if assert.Len(t, errs, 2) {
+			assert.EqualError(t, errs[0], "element 1: test error")
+			assert.ErrorIs(t, errs[0], expectedErr)
+			assert.EqualError(t, errs[1], "element 3: assertion failed")
+		}
+	})
+}
+
+func TestNormalizeRecovered(t *testing.T) {
+	t.Run("with nil returns nil", func(t *testing.T) {
+		assert.NoError(t, NormalizeRecovered(nil))
+	})
+
+	t.Run("with an error returns the error", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		assert.Equal(t, expectedErr, NormalizeRecovered(expectedErr))
+	})
+
+	t.Run("with an AssertionError returns the underlying error", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		assert.Equal(t, expectedErr, NormalizeRecovered(&AssertionError{Err: expectedErr}))
+	})
+
+	t.Run("with a wrapped AssertionError preserves the outer context", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		wrapped := fmt.Errorf("loading config: %w", &AssertionError{Err: expectedErr})
+		err := NormalizeRecovered(wrapped)
+		assert.Equal(t, wrapped, err)
+		assert.EqualError(t, err, "loading config: test error")
+		assert.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("with a string returns a wrapped error", func(t *testing.T) {
+		assert.EqualError(t, NormalizeRecovered("test value"), "panic: test value")
+	})
+
+	t.Run("with an int returns a wrapped error", func(t *testing.T) {
+		assert.EqualError(t, NormalizeRecovered(17), "panic: 17")
+	})
+}
+
+func TestHandleRecovered(t *testing.T) {
+	t.Run("with nil does not call the handler", func(t *testing.T) {
+		var called bool
+		HandleRecovered(nil, func(err error) { called = true })
+		assert.False(t, called)
+	})
+
+	t.Run("with an AssertionError calls the handler", func(t *testing.T) {
+		var got error
+		assert.NotPanics(t, func() {
+			defer func() {
+				HandleRecovered(recover(), func(err error) { got = err })
+			}()
+			Assert(false)
+		})
+		assert.EqualError(t, got, "assertion failed")
+	})
+
+	t.Run("with a PanicOnErrorN error calls the handler", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		var got error
+		assert.NotPanics(t, func() {
+			defer func() {
+				HandleRecovered(recover(), func(err error) { got = err })
+			}()
+			PanicOnError0(expectedErr)
+		})
+		assert.Equal(t, expectedErr, got)
+	})
+
+	t.Run("with a Must helper error calls the handler", func(t *testing.T) {
+		var got error
+		assert.NotPanics(t, func() {
+			defer func() {
+				HandleRecovered(recover(), func(err error) { got = err })
+			}()
+			MustCast[string](17)
+		})
+		assert.EqualError(t, got, "cannot cast int to string")
+	})
+
+	t.Run("with a third-party error re-panics", func(t *testing.T) {
+		var called bool
+		expectedErr := errors.New("test error")
+		assert.PanicsWithValue(t, expectedErr, func() {
+			HandleRecovered(expectedErr, func(err error) { called = true })
+		})
+		assert.False(t, called)
+	})
+
+	t.Run("with a string re-panics", func(t *testing.T) {
+		var called bool
+		assert.PanicsWithValue(t, "test value", func() {
+			HandleRecovered("test value", func(err error) { called = true })
+		})
+		assert.False(t, called)
+	})
+
+	t.Run("with a runtime error re-panics", func(t *testing.T) {
+		var called bool
+		r := callAndRecover(func() {
+			var m map[string]int
+			m["key"] = 17
+		})
+		assert.PanicsWithValue(t, r, func() {
+			HandleRecovered(r, func(err error) { called = true })
+		})
+		assert.False(t, called)
+	})
+}
+
+func TestRecoverAndExit(t *testing.T) {
+	// Save original logFatal and restore after each test
+	originalLogFatal := logFatal
+	defer func() { logFatal = originalLogFatal }()
+
+	var fatalCalled bool
+	var fatalValue any
+	logFatal = func(v ...any) {
+		fatalCalled = true
+		fatalValue = v[0]
+	}
+
+	// Reset mocks before each subtest
+	resetMocks := func() {
+		fatalCalled = false
+		fatalValue = nil
+	}
+
+	t.Run("without panic does nothing", func(t *testing.T) {
+		resetMocks()
+		func() {
+			defer RecoverAndExit()
+		}()
+		assert.False(t, fatalCalled)
+	})
+
+	t.Run("with error panic logs the error", func(t *testing.T) {
+		resetMocks()
+		expectedErr := errors.New("test error")
+		func() {
+			defer RecoverAndExit()
+			PanicOnError0(expectedErr)
+		}()
+		assert.True(t, fatalCalled)
+		assert.Equal(t, expectedErr, fatalValue)
+	})
+
+	t.Run("with string panic logs a wrapped error", func(t *testing.T) {
+		resetMocks()
+		func() {
+			defer RecoverAndExit()
+			panic("test value")
+		}()
+		assert.True(t, fatalCalled)
+		assert.EqualError(t, fatalValue.(error), "panic: test value")
+	})
+}
+
+func TestSafeGo(t *testing.T) {
+	t.Run("with normal return does not call onError", func(t *testing.T) {
+		done := make(chan struct{})
+		errch := make(chan error, 1)
+		SafeGo(func() {
+			close(done)
+		}, func(err error) {
+			errch <- err
+		})
+		<-done
+		select {
+		case err := <-errch:
+			t.Fatal("unexpected call to onError", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	})
+
+	t.Run("with error panic calls onError", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		errch := make(chan error, 1)
+		SafeGo(func() {
+			PanicOnError0(expectedErr)
+		}, func(err error) {
+			errch <- err
+		})
+		assert.Equal(t, expectedErr, <-errch)
+	})
+
+	t.Run("with assertion panic calls onError with the underlying error", func(t *testing.T) {
+		errch := make(chan error, 1)
+		SafeGo(func() {
+			Assert(false)
+		}, func(err error) {
+			errch <- err
+		})
+		err := <-errch
+		assert.False(t, IsAssertionError(err))
+		assert.EqualError(t, err, "assertion failed")
+	})
+
+	t.Run("with string panic calls onError with a wrapped error", func(t *testing.T) {
+		errch := make(chan error, 1)
+		SafeGo(func() {
+			panic("test value")
+		}, func(err error) {
+			errch <- err
+		})
+		assert.EqualError(t, <-errch, "panic: test value")
+	})
+}
+
+func TestGuardBoundary(t *testing.T) {
+	t.Run("without panic does not call the logger", func(t *testing.T) {
+		var logged error
+		called := false
+		GuardBoundary(func(err error) { logged = err }, true, func() {
+			called = true
+		})
+		assert.True(t, called)
+		assert.NoError(t, logged)
+	})
+
+	t.Run("with panic and rethrow false logs and returns", func(t *testing.T) {
+		var logged error
+		assert.NotPanics(t, func() {
+			GuardBoundary(func(err error) { logged = err }, false, func() {
+				Assert(false)
+			})
+		})
+		assert.False(t, IsAssertionError(logged))
+		assert.EqualError(t, logged, "assertion failed")
+	})
+
+	t.Run("with panic and rethrow true logs and re-panics", func(t *testing.T) {
+		var logged error
+		assert.PanicsWithValue(t, "test value", func() {
+			GuardBoundary(func(err error) { logged = err }, true, func() {
+				panic("test value")
+			})
+		})
+		assert.EqualError(t, logged, "panic: test value")
+	})
+}
+
+func TestRecoverToError(t *testing.T) {
+	// recoverToError returns fn's error after deferring RecoverToError.
+	recoverToError := func(fn func() error) (err error) {
+		defer RecoverToError(&err)
+		return fn()
+	}
+
+	t.Run("without panic does not clobber the error", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		err := recoverToError(func() error {
+			return expectedErr
+		})
+		assert.Equal(t, expectedErr, err)
+	})
+
+	t.Run("without panic leaves nil error", func(t *testing.T) {
+		err := recoverToError(func() error {
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("with error panic assigns the error", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		err := recoverToError(func() error {
+			PanicOnError0(expectedErr)
+			return nil
+		})
+		assert.Equal(t, expectedErr, err)
+	})
+
+	t.Run("with assertion panic assigns the underlying error", func(t *testing.T) {
+		err := recoverToError(func() error {
+			Assert(false)
+			return nil
+		})
+		assert.False(t, IsAssertionError(err))
+		assert.EqualError(t, err, "assertion failed")
+	})
+
+	t.Run("with string panic assigns a wrapped error", func(t *testing.T) {
+		err := recoverToError(func() error {
+			panic("test value")
+		})
+		assert.EqualError(t, err, "panic: test value")
+	})
+}
+
+func TestAssertNoPanic(t *testing.T) {
+	t.Run("without panic does not panic", func(t *testing.T) {
+		called := false
+		assert.NotPanics(t, func() {
+			AssertNoPanic(func() {
+				called = true
+			})
+		})
+		assert.True(t, called)
+	})
+
+	t.Run("with error panic panics with wrapped error", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		r := AssertPanics(func() {
+			AssertNoPanic(func() {
+				PanicOnError0(expectedErr)
+			})
+		})
+		assert.True(t, IsAssertionError(r))
+		assert.EqualError(t, r.(error), "unexpected panic: test error")
+		assert.True(t, errors.Is(r.(error), expectedErr))
+	})
+
+	t.Run("with string panic panics with wrapped value", func(t *testing.T) {
+		assert.PanicsWithError(t, "unexpected panic: test value", func() {
+			AssertNoPanic(func() {
+				panic("test value")
+			})
+		})
+	})
+}
+
+func TestAssertPanics(t *testing.T) {
+	t.Run("with panic returns the recovered value", func(t *testing.T) {
+		var r any
+		assert.NotPanics(t, func() {
+			r = AssertPanics(func() {
+				panic("test value")
+			})
+		})
+		assert.Equal(t, "test value", r)
+	})
+
+	t.Run("without panic panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "expected panic", func() {
+			AssertPanics(func() {})
+		})
+	})
+}
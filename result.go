@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+// Result contains either a value or an error.
+//
+// Use [Wrap] to construct a Result from a function returning `(T, error)`
+// so that you can store a fallible result and decide later whether to
+// panic or to fall back to a default value. For example:
+//
+//	data := runtimex.Wrap(os.ReadFile(path)).UnwrapOr(nil)
+//
+// The zero value is a valid Result containing the zero value of T.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Ok returns a [Result] containing the given value.
+func Ok[T any](v T) Result[T] {
+	return Result[T]{value: v}
+}
+
+// Err returns a [Result] containing the given error.
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// Wrap returns a [Result] containing the given value and error.
+func Wrap[T any](v T, err error) Result[T] {
+	return Result[T]{value: v, err: err}
+}
+
+// Unwrap returns the value if there is no error and otherwise
+// panics using [PanicOnError1] with the stored error.
+func (r Result[T]) Unwrap() T {
+	panicOnError(1, r.err)
+	return r.value
+}
+
+// UnwrapOr returns the value if there is no error and def otherwise.
+func (r Result[T]) UnwrapOr(def T) T {
+	if r.err != nil {
+		return def
+	}
+	return r.value
+}
+
+// IsOk returns whether there is no error.
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// Err returns the stored error, or nil.
+func (r Result[T]) Err() error {
+	return r.err
+}
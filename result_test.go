@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResult(t *testing.T) {
+	expectedErr := errors.New("test error")
+
+	t.Run("Ok", func(t *testing.T) {
+		r := Ok("value")
+		assert.True(t, r.IsOk())
+		assert.NoError(t, r.Err())
+		assert.Equal(t, "value", r.Unwrap())
+		assert.Equal(t, "value", r.UnwrapOr("default"))
+	})
+
+	t.Run("Err", func(t *testing.T) {
+		r := Err[string](expectedErr)
+		assert.False(t, r.IsOk())
+		assert.Equal(t, expectedErr, r.Err())
+		assert.Equal(t, "default", r.UnwrapOr("default"))
+		ae := recoverAssertionError(func() {
+			r.Unwrap()
+		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+
+	t.Run("Wrap with nil error", func(t *testing.T) {
+		r := Wrap(17, nil)
+		assert.True(t, r.IsOk())
+		assert.Equal(t, 17, r.Unwrap())
+	})
+
+	t.Run("Wrap with non-nil error", func(t *testing.T) {
+		r := Wrap(17, expectedErr)
+		assert.False(t, r.IsOk())
+		assert.Equal(t, expectedErr, r.Err())
+		assert.Equal(t, 42, r.UnwrapOr(42))
+		ae := recoverAssertionError(func() {
+			r.Unwrap()
+		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var r Result[int]
+		assert.True(t, r.IsOk())
+		assert.Equal(t, 0, r.Unwrap())
+	})
+}
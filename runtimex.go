@@ -24,12 +24,18 @@
 package runtimex
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"slices"
+	"strings"
+	"sync/atomic"
 )
 
 // Assert panics if the given value is false. The value passed to
-// `panic()` is an error constructed using [errors.New].
+// `panic()` is an [*AssertionError] wrapping an error constructed
+// using [errors.New].
 //
 // You typically use this function to assert runtime invariants in your codebase
 // to make it more robust. Document the invariant and its justification in a
@@ -42,13 +48,109 @@ import (
 // The correct approach is to assert for conditions that should be
 // impossible if the program is correct.
 func Assert(value bool) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
 	if !value {
-		panic(errors.New("assertion failed"))
+		assertionFailed(errors.New("assertion failed"))
+	}
+}
+
+// errStaticAssertion is the value passed to `panic()` by [AssertStatic].
+var errStaticAssertion = &AssertionError{Err: errors.New("assertion failed")}
+
+// AssertStatic is like [Assert] but is trivially inlinable, such that it has
+// no function call overhead. It is the fastest assertion, meant for cheap
+// conditions in hot loops, where profiling shows that [Assert] is too costly.
+// The value passed to `panic()` is always the same [*AssertionError], whose
+// message is `assertion failed`.
+//
+// To be so cheap, this function intentionally bypasses the configuration of
+// the other assertion functions: it does not run the [OnAssertionFailure]
+// hooks, ignores [SetAssertionMode] and [UseTestingTB], does not capture the
+// stack or the caller, and is not removed by the `runtimex_noassert` tag.
+func AssertStatic(cond bool) {
+	if !cond {
+		panic(errStaticAssertion)
+	}
+}
+
+// Assertf is like [Assert] but the value passed to `panic()` wraps an
+// error constructed using [fmt.Errorf] with the given format and args. For example:
+//
+//	runtimex.Assertf(idx < len(buf), "index %d out of range %d", idx, len(buf))
+func Assertf(value bool, format string, args ...any) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !value {
+		assertionFailed(fmt.Errorf(format, args...))
+	}
+}
+
+// AssertLazy is like [Assertf] but builds the message by calling msg only
+// when value is false. With [Assertf], Go evaluates and boxes the format
+// arguments even when the assertion holds, which may matter in hot loops.
+// The price is allocating a closure when it captures variables that escape.
+// Prefer [Assertf] unless profiling shows that the arguments are costly. For example:
+//
+//	runtimex.AssertLazy(ok, func() string {
+//		return fmt.Sprintf("unexpected state: %+v", state)
+//	})
+func AssertLazy(value bool, msg func() string) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !value {
+		assertionFailed(errors.New(msg()))
+	}
+}
+
+// AssertUnreachable marks code that should never execute, e.g., the default
+// branch of an exhaustive switch, and always panics. The value passed to `panic()`
+// is an [*AssertionError] whose message includes reason, i.e., `unreachable code
+// reached: <reason>`. For example:
+//
+//	switch mode {
+//	case ModeA:
+//		return handleA()
+//	case ModeB:
+//		return handleB()
+//	default:
+//		runtimex.AssertUnreachable("mode validated by the constructor")
+//		return nil
+//	}
+//
+// Since the code following the call assumes it does not return, this function
+// panics even when assertions are disabled or in [ModeWarn]. The Go compiler
+// does not know that it never returns, hence the `return nil` above.
+func AssertUnreachable(reason string) {
+	err := fmt.Errorf("unreachable code reached: %s", reason)
+	assertionFailed(err)
+	panic(&AssertionError{Err: err}) // with [ModeWarn] or a [TB] whose Fatalf returns
+}
+
+// AssertThat is like [Assertf] but checks the predicate pred against v and
+// appends v to the message, such that the offending value is not lost. The
+// value passed to `panic()` wraps an error constructed using [fmt.Errorf]
+// with format followed by ": got %v" and args followed by v. For example:
+//
+//	runtimex.AssertThat(port, func(p int) bool { return p > 0 && p < 65536 }, "invalid port")
+//
+// panics with an error whose message is "invalid port: got <port>".
+func AssertThat[T any](v T, pred func(T) bool, format string, args ...any) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !pred(v) {
+		assertionFailed(fmt.Errorf(format+": got %v", append(args, v)...))
 	}
 }
 
 // PanicOnError0 panics if the given err is not nil. The value passed
-// to `panic()` is the given err value.
+// to `panic()` is an [*AssertionError] wrapping err, such that recover
+// handlers can tell it apart from panics originating elsewhere using
+// [IsAssertionError]. The value still matches err using [errors.Is].
 //
 // You typically use this function to assert runtime invariants
 // in your codebase to make it more robust. For example:
@@ -59,13 +161,41 @@ func Assert(value bool) {
 // possibly happen (e.g., [json.Marshal] applied to a struct
 // that can always be marshalled to a JSON string).
 func PanicOnError0(err error) {
-	if err != nil {
-		panic(err)
+	if err != nil || atomic.LoadInt32(&metricsEnabled) != 0 {
+		panicOnError(1, err)
 	}
 }
 
+// PanicOnError0f is like [PanicOnError0] but the [*AssertionError] passed to
+// `panic()` wraps err using [fmt.Errorf] and prepends the formatted context.
+// The resulting error still matches err when using [errors.Is]. For example:
+//
+//	runtimex.PanicOnError0f(os.Chdir(dir), "chdir %s", dir)
+//
+// panics with an error whose message is "chdir <dir>: <err>".
+func PanicOnError0f(err error, format string, args ...any) {
+	panicOnErrorf(1, err, format, args...)
+}
+
+// PanicOnError0fDepth is like [PanicOnError0f] but meant for helper functions
+// wrapping it in other packages, e.g., [github.com/bassosimone/runtimex/mustio].
+// The depth argument is the number of helper stack frames to skip such that
+// [SetIncludeCaller] and [SetCaptureStack] point to the code invoking the
+// helper rather than to the helper itself. For example:
+//
+//	func MustReadFile(path string) []byte {
+//		data, err := os.ReadFile(path)
+//		runtimex.PanicOnError0fDepth(1, err, "cannot read %s", path)
+//		return data
+//	}
+//
+// With depth zero, this function is equivalent to [PanicOnError0f].
+func PanicOnError0fDepth(depth int, err error, format string, args ...any) {
+	panicOnErrorf(depth+1, err, format, args...)
+}
+
 // PanicOnError1 panics if the given err is not nil. The value passed
-// to `panic()` is the given err value. Otherwise, it returns the given
+// to `panic()` is an [*AssertionError] wrapping err. Otherwise, it returns the given
 // value `v1`.
 //
 // This code is equivalent to:
@@ -77,14 +207,26 @@ func PanicOnError0(err error) {
 //
 // but is more compact and improves readability when chaining operations.
 func PanicOnError1[T1 any](v1 T1, err error) T1 {
-	if err != nil {
-		panic(err)
+	if err != nil || atomic.LoadInt32(&metricsEnabled) != 0 {
+		panicOnError(1, err)
 	}
 	return v1
 }
 
+// PanicOnError1f is like [PanicOnError1] but the value passed to `panic()`
+// wraps err and prepends the formatted context like [PanicOnError0f]. Since
+// Go does not allow passing a multi-value call along with other arguments,
+// you need to pass v1 and err explicitly. For example:
+//
+//	data, err := os.ReadFile(path)
+//	data = runtimex.PanicOnError1f(data, err, "reading %s", path)
+func PanicOnError1f[T1 any](v1 T1, err error, format string, args ...any) T1 {
+	panicOnErrorf(1, err, format, args...)
+	return v1
+}
+
 // PanicOnError2 panics if the given err is not nil. The value passed
-// to `panic()` is the given err value. Otherwise, it returns the given
+// to `panic()` is an [*AssertionError] wrapping err. Otherwise, it returns the given
 // values `v1` and `v2`.
 //
 // This code is equivalent to:
@@ -96,14 +238,14 @@ func PanicOnError1[T1 any](v1 T1, err error) T1 {
 //
 // but is more compact and improves readability when chaining operations.
 func PanicOnError2[T1, T2 any](v1 T1, v2 T2, err error) (T1, T2) {
-	if err != nil {
-		panic(err)
+	if err != nil || atomic.LoadInt32(&metricsEnabled) != 0 {
+		panicOnError(1, err)
 	}
 	return v1, v2
 }
 
 // PanicOnError3 panics if the given err is not nil. The value passed
-// to `panic()` is the given err value. Otherwise, it returns the given
+// to `panic()` is an [*AssertionError] wrapping err. Otherwise, it returns the given
 // values `v1`, v2, and `v3`.
 //
 // This code is equivalent to:
@@ -115,14 +257,242 @@ func PanicOnError2[T1, T2 any](v1 T1, v2 T2, err error) (T1, T2) {
 //
 // but is more compact and improves readability when chaining operations.
 func PanicOnError3[T1, T2, T3 any](v1 T1, v2 T2, v3 T3, err error) (T1, T2, T3) {
-	if err != nil {
-		panic(err)
+	if err != nil || atomic.LoadInt32(&metricsEnabled) != 0 {
+		panicOnError(1, err)
 	}
 	return v1, v2, v3
 }
 
+// PanicOnError4 panics if the given err is not nil. The value passed
+// to `panic()` is an [*AssertionError] wrapping err. Otherwise, it returns the given
+// values `v1`, `v2`, `v3`, and `v4`.
+//
+// This code is equivalent to:
+//
+//	v1, v2, v3, v4, err := fx()
+//	if err != nil {
+//		panic(err)
+//	}
+//
+// but is more compact and improves readability when chaining operations.
+func PanicOnError4[T1, T2, T3, T4 any](v1 T1, v2 T2, v3 T3, v4 T4, err error) (T1, T2, T3, T4) {
+	if err != nil || atomic.LoadInt32(&metricsEnabled) != 0 {
+		panicOnError(1, err)
+	}
+	return v1, v2, v3, v4
+}
+
+// PanicOnError5 panics if the given err is not nil. The value passed
+// to `panic()` is an [*AssertionError] wrapping err. Otherwise, it returns the given
+// values `v1`, `v2`, `v3`, `v4`, and `v5`.
+//
+// This code is equivalent to:
+//
+//	v1, v2, v3, v4, v5, err := fx()
+//	if err != nil {
+//		panic(err)
+//	}
+//
+// but is more compact and improves readability when chaining operations.
+func PanicOnError5[T1, T2, T3, T4, T5 any](
+	v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, err error) (T1, T2, T3, T4, T5) {
+	if err != nil || atomic.LoadInt32(&metricsEnabled) != 0 {
+		panicOnError(1, err)
+	}
+	return v1, v2, v3, v4, v5
+}
+
+// PanicOnError6 panics if the given err is not nil. The value passed
+// to `panic()` is an [*AssertionError] wrapping err. Otherwise, it returns the given
+// values `v1`, `v2`, `v3`, `v4`, `v5`, and `v6`.
+//
+// This code is equivalent to:
+//
+//	v1, v2, v3, v4, v5, v6, err := fx()
+//	if err != nil {
+//		panic(err)
+//	}
+//
+// but is more compact and improves readability when chaining operations.
+func PanicOnError6[T1, T2, T3, T4, T5, T6 any](
+	v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6, err error) (T1, T2, T3, T4, T5, T6) {
+	if err != nil || atomic.LoadInt32(&metricsEnabled) != 0 {
+		panicOnError(1, err)
+	}
+	return v1, v2, v3, v4, v5, v6
+}
+
+// PanicOnErrorAny is the dynamic-arity version of the PanicOnErrorN family
+// for reflective or generated code. The last element of results must be an
+// error or nil. If it is a non-nil error, PanicOnErrorAny panics like
+// [PanicOnError0]. Otherwise, it returns the leading elements of results.
+//
+// It panics with an error if results is empty or if its last element
+// is neither nil nor an error, since that is a programmer error.
+func PanicOnErrorAny(results ...any) []any {
+	if len(results) <= 0 {
+		panic(errors.New("PanicOnErrorAny: expected at least one argument"))
+	}
+	last := results[len(results)-1]
+	err, ok := last.(error)
+	if last != nil && !ok {
+		panic(fmt.Errorf("PanicOnErrorAny: last argument must be an error, got %T", last))
+	}
+	panicOnError(1, err)
+	return results[:len(results)-1]
+}
+
 // logFatal is a variable so we can replace it during testing.
-var logFatal = log.Fatal
+//
+// Unlike [log.Fatal], it exits through [exitProcess] such that the
+// functions registered with [RegisterExitCleanup] run before exiting.
+var logFatal = func(v ...any) {
+	logPrint(v...)
+	exitProcess(1)
+}
+
+// logPrint is a variable so we can replace it during testing.
+var logPrint = log.Print
+
+// fatalLogger is the logger configured using [SetFatalLogger].
+var fatalLogger func(msg string, args ...any)
+
+// SetFatalLogger configures the LogFatalOnErrorN family to log using fn
+// rather than [log.Print]. The fn signature matches the methods of
+// [*slog.Logger] such that you can route fatal errors through slog:
+//
+//	runtimex.SetFatalLogger(logger.Error)
+//
+// On error, fn is called with "fatal error" as msg and with "err" and the
+// error as args, and then the process exits with status code 1.
+//
+// Passing nil restores the default behavior of logging using [log.Print]
+// and then exiting, after running the functions registered with
+// [RegisterExitCleanup]. This function is not goroutine safe and should be
+// called at program startup.
+func SetFatalLogger(fn func(msg string, args ...any)) {
+	fatalLogger = fn
+}
+
+// FatalFormat selects the output format of the LogFatalOnErrorN family.
+type FatalFormat int
+
+const (
+	// FormatText logs the error using [log.Print]. This is the default.
+	FormatText FatalFormat = iota
+
+	// FormatJSON writes a single JSON object on the writer configured using
+	// [SetDefaultErrorWriter], which is the standard error by default, e.g.:
+	//
+	//	{"level":"fatal","msg":"cannot open config","error":"file not found"}
+	//
+	// where msg contains the message qualifiers, if any, joined by spaces
+	// or by the separator configured using [SetFatalSeparator], and otherwise
+	// is "fatal error", and error is the error message.
+	FormatJSON
+)
+
+// fatalFormat is the format configured using [SetFatalFormat].
+var fatalFormat = FormatText
+
+// SetFatalFormat configures the output format of the LogFatalOnErrorN family,
+// which exits with a fatal error after writing in the given format. Use
+// [FormatJSON] when the logs pipeline only ingests JSON. The default is
+// [FormatText]. A logger configured with [SetFatalLogger] takes precedence
+// over the format. This function is not goroutine safe and should be called
+// at program startup.
+func SetFatalFormat(format FatalFormat) {
+	fatalFormat = format
+}
+
+// fatalJSONRecord is the record written by [FormatJSON].
+type fatalJSONRecord struct {
+	Level string `json:"level"`
+	Msg   string `json:"msg"`
+	Error string `json:"error"`
+}
+
+// writeFatalJSON writes err, msgs, and attrs as a [fatalJSONRecord] to [fatalWriter]
+// followed by the attrs key-value pairs, which must have an even length.
+func writeFatalJSON(err error, msgs []string, attrs []any) {
+	record := fatalJSONRecord{Level: "fatal", Msg: "fatal error", Error: err.Error()}
+	if len(msgs) > 0 {
+		record.Msg = joinFatalMsgs(msgs...)
+	}
+	data, _ := json.Marshal(record) // cannot fail with a struct of strings
+	data = data[:len(data)-1]       // remove the closing brace
+	for idx := 0; idx+1 < len(attrs); idx += 2 {
+		key, _ := json.Marshal(fmt.Sprint(attrs[idx])) // cannot fail with a string
+		data = append(append(append(data, ','), key...), ':')
+		data = append(data, marshalFatalAttrValue(attrs[idx+1])...)
+	}
+	fatalWriter.Write(append(data, '}', '\n'))
+}
+
+// marshalFatalAttrValue marshals v to JSON, using the message of errors and
+// falling back to the [fmt.Sprint] representation of values that cannot be
+// marshaled, such that we never lose an attribute value.
+func marshalFatalAttrValue(v any) []byte {
+	if err, ok := v.(error); ok {
+		v = err.Error()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v)) // cannot fail with a string
+	}
+	return data
+}
+
+// missingAttrValue is the value we use for a key without value in the
+// attrs passed to [LogFatalOnErrorAttrs].
+const missingAttrValue = "!MISSING"
+
+// normalizeFatalAttrs returns attrs with an even length, by appending
+// [missingAttrValue] as the value of a trailing key without value.
+func normalizeFatalAttrs(attrs []any) []any {
+	if len(attrs)%2 != 0 {
+		attrs = append(slices.Clip(attrs), missingAttrValue)
+	}
+	return attrs
+}
+
+// withFatalTextAttrs returns err followed by the attrs key-value pairs, which
+// must have an even length, formatted as space-separated key=value text.
+func withFatalTextAttrs(err error, attrs []any) error {
+	if len(attrs) <= 0 {
+		return err
+	}
+	var sb strings.Builder
+	for idx := 0; idx+1 < len(attrs); idx += 2 {
+		fmt.Fprintf(&sb, " %v=%v", attrs[idx], attrs[idx+1])
+	}
+	return fmt.Errorf("%w%s", err, sb.String())
+}
+
+// logFatalError logs err using the configured fatal logger and exits.
+func logFatalError(err error) {
+	if fatalLogger == nil && fatalFormat == FormatText {
+		logFatal(err)
+		return
+	}
+	logErrorAndExit(1, err, nil)
+}
+
+// logErrorAndExit logs err, prefixed by the msgs qualifiers and followed by
+// the attrs key-value pairs, which must have an even length, using the
+// configured fatal logger or format, without exiting, and then exits with
+// the given status code.
+func logErrorAndExit(code int, err error, msgs []string, attrs ...any) {
+	switch {
+	case fatalLogger != nil:
+		fatalLogger("fatal error", append([]any{"err", wrapFatalError(err, msgs...)}, attrs...)...)
+	case fatalFormat == FormatJSON:
+		writeFatalJSON(err, msgs, attrs)
+	default:
+		logPrint(withFatalTextAttrs(wrapFatalError(err, msgs...), attrs))
+	}
+	exitProcess(code)
+}
 
 // LogFatalOnError0 exits with a fatal error if err is not nil.
 //
@@ -133,7 +503,21 @@ var logFatal = log.Fatal
 //	}
 func LogFatalOnError0(err error) {
 	if err != nil {
-		logFatal(err)
+		logFatalError(err)
+	}
+}
+
+// LogFatalOnError0f is like [LogFatalOnError0] but prepends the formatted
+// context to err, such that the logged message is "<context>: <err>".
+//
+// It is equivalent to:
+//
+//	if err != nil {
+//		log.Fatal(fmt.Errorf(format+": %w", append(args, err)...))
+//	}
+func LogFatalOnError0f(err error, format string, args ...any) {
+	if err != nil {
+		logFatalError(fmt.Errorf(format+": %w", append(args, err)...))
 	}
 }
 
@@ -148,7 +532,7 @@ func LogFatalOnError0(err error) {
 //	}
 func LogFatalOnError1[T1 any](v1 T1, err error) T1 {
 	if err != nil {
-		logFatal(err)
+		logFatalError(err)
 	}
 	return v1
 }
@@ -164,7 +548,7 @@ func LogFatalOnError1[T1 any](v1 T1, err error) T1 {
 //	}
 func LogFatalOnError2[T1, T2 any](v1 T1, v2 T2, err error) (T1, T2) {
 	if err != nil {
-		logFatal(err)
+		logFatalError(err)
 	}
 	return v1, v2
 }
@@ -180,7 +564,46 @@ func LogFatalOnError2[T1, T2 any](v1 T1, v2 T2, err error) (T1, T2) {
 //	}
 func LogFatalOnError3[T1, T2, T3 any](v1 T1, v2 T2, v3 T3, err error) (T1, T2, T3) {
 	if err != nil {
-		logFatal(err)
+		logFatalError(err)
 	}
 	return v1, v2, v3
 }
+
+// LogFatalOnErrorCode is like [LogFatalOnError0] but exits with the given
+// status code. The logged message consists of msgs joined by spaces,
+// followed by a colon, a space, and err. Without msgs, it is just err.
+//
+// It is equivalent to:
+//
+//	if err != nil {
+//		log.Print(err)
+//		os.Exit(code)
+//	}
+func LogFatalOnErrorCode(code int, err error, msgs ...string) {
+	if err != nil {
+		logErrorAndExit(code, err, msgs)
+	}
+}
+
+// LogFatalOnErrorAttrs is like [LogFatalOnError0] but also logs the given
+// attrs, which are alternating keys and values, like the arguments of the
+// methods of [*slog.Logger]. For example:
+//
+//	runtimex.LogFatalOnErrorAttrs(err, "path", path, "attempt", 3)
+//
+// With a logger configured using [SetFatalLogger], the attrs follow "err"
+// and the error in the logger args. With [FormatJSON], they are additional
+// fields of the JSON object. Otherwise, they are appended to the logged
+// message as space-separated key=value text, e.g., "<err> path=/etc attempt=3".
+//
+// A trailing key without value gets "!MISSING" as its value.
+func LogFatalOnErrorAttrs(err error, attrs ...any) {
+	if err != nil {
+		attrs = normalizeFatalAttrs(attrs)
+		if fatalLogger == nil && fatalFormat == FormatText {
+			logFatal(withFatalTextAttrs(err, attrs))
+			return
+		}
+		logErrorAndExit(1, err, nil, attrs...)
+	}
+}
@@ -3,7 +3,12 @@
 package runtimex
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"log/slog"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -23,6 +28,119 @@ func TestAssert(t *testing.T) {
 	})
 }
 
+func TestAssertf(t *testing.T) {
+	t.Run("with true value does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			Assertf(true, "index %d out of range %d", 4, 3)
+		})
+	})
+
+	t.Run("with false value panics with formatted message", func(t *testing.T) {
+		assert.PanicsWithError(t, "index 4 out of range 3", func() {
+			Assertf(false, "index %d out of range %d", 4, 3)
+		})
+	})
+}
+
+func TestAssertLazy(t *testing.T) {
+	t.Run("with true value does not call msg", func(t *testing.T) {
+		called := false
+		assert.NotPanics(t, func() {
+			AssertLazy(true, func() string {
+				called = true
+				return "message"
+			})
+		})
+		assert.False(t, called)
+	})
+
+	t.Run("with false value panics with msg", func(t *testing.T) {
+		called := false
+		assert.PanicsWithError(t, "message", func() {
+			AssertLazy(false, func() string {
+				called = true
+				return "message"
+			})
+		})
+		assert.True(t, called)
+	})
+}
+
+func TestAssertUnreachable(t *testing.T) {
+	t.Run("always panics with the reason", func(t *testing.T) {
+		err := recoverError(func() {
+			AssertUnreachable("mode validated by the constructor")
+		})
+		assert.True(t, IsAssertionError(err))
+		assert.EqualError(t, err, "unreachable code reached: mode validated by the constructor")
+	})
+
+	t.Run("panics when assertions are disabled", func(t *testing.T) {
+		SetAssertionsEnabled(false)
+		defer SetAssertionsEnabled(true)
+		assert.PanicsWithError(t, "unreachable code reached: reason", func() {
+			AssertUnreachable("reason")
+		})
+	})
+
+	t.Run("panics in warn mode after warning", func(t *testing.T) {
+		SetAssertionMode(ModeWarn)
+		defer SetAssertionMode(ModePanic)
+		var warned error
+		SetWarnLogger(func(err error) { warned = err })
+		defer SetWarnLogger(nil)
+		assert.PanicsWithError(t, "unreachable code reached: reason", func() {
+			AssertUnreachable("reason")
+		})
+		assert.EqualError(t, warned, "unreachable code reached: reason")
+	})
+}
+
+func TestAssertStatic(t *testing.T) {
+	t.Run("with true value does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertStatic(true)
+		})
+	})
+
+	t.Run("with false value panics", func(t *testing.T) {
+		err := recoverError(func() {
+			AssertStatic(false)
+		})
+		assert.True(t, IsAssertionError(err))
+		assert.EqualError(t, err, "assertion failed")
+	})
+
+	t.Run("ignores the assertion mode", func(t *testing.T) {
+		SetAssertionMode(ModeDisabled)
+		defer SetAssertionMode(ModePanic)
+		assert.Panics(t, func() {
+			AssertStatic(false)
+		})
+	})
+}
+
+func TestAssertThat(t *testing.T) {
+	t.Run("with an int satisfying the predicate does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertThat(8080, func(p int) bool { return p > 0 && p < 65536 }, "invalid port")
+		})
+	})
+
+	t.Run("with an int violating the predicate panics with the value", func(t *testing.T) {
+		assert.PanicsWithError(t, "invalid port for listener main: got 70000", func() {
+			AssertThat(70000, func(p int) bool { return p > 0 && p < 65536 }, "invalid port for listener %s", "main")
+		})
+	})
+
+	t.Run("with a struct violating the predicate panics with the value", func(t *testing.T) {
+		v := comparableStruct{Name: "test", Value: -1}
+		assert.PanicsWithError(t, "negative value: got {test -1}", func() {
+			AssertThat(v, func(v comparableStruct) bool { return v.Value >= 0 }, "negative value")
+		})
+	})
+}
+
 func TestPanicOnError0(t *testing.T) {
 	t.Run("with nil error does not panic", func(t *testing.T) {
 		assert.NotPanics(t, func() {
@@ -32,9 +150,28 @@ func TestPanicOnError0(t *testing.T) {
 
 	t.Run("with non-nil error panics", func(t *testing.T) {
 		expectedErr := errors.New("test error")
-		assert.PanicsWithValue(t, expectedErr, func() {
+		ae := recoverAssertionError(func() {
 			PanicOnError0(expectedErr)
 		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+}
+
+func TestPanicOnError0f(t *testing.T) {
+	t.Run("with nil error does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			PanicOnError0f(nil, "reading %s", "file.txt")
+		})
+	})
+
+	t.Run("with non-nil error panics with wrapped error", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		defer func() {
+			err := recover().(error)
+			assert.Equal(t, "reading file.txt: test error", err.Error())
+			assert.True(t, errors.Is(err, expectedErr))
+		}()
+		PanicOnError0f(expectedErr, "reading %s", "file.txt")
 	})
 }
 
@@ -50,9 +187,30 @@ func TestPanicOnError1(t *testing.T) {
 
 	t.Run("with non-nil error panics", func(t *testing.T) {
 		expectedErr := errors.New("test error")
-		assert.PanicsWithValue(t, expectedErr, func() {
+		ae := recoverAssertionError(func() {
 			PanicOnError1("value", expectedErr)
 		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+}
+
+func TestPanicOnError1f(t *testing.T) {
+	t.Run("with nil error returns value", func(t *testing.T) {
+		var result string
+		assert.NotPanics(t, func() {
+			result = PanicOnError1f("test value", nil, "reading %s", "file.txt")
+		})
+		assert.Equal(t, "test value", result)
+	})
+
+	t.Run("with non-nil error panics with wrapped error", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		defer func() {
+			err := recover().(error)
+			assert.Equal(t, "reading file.txt: test error", err.Error())
+			assert.True(t, errors.Is(err, expectedErr))
+		}()
+		PanicOnError1f("test value", expectedErr, "reading %s", "file.txt")
 	})
 }
 
@@ -71,9 +229,10 @@ func TestPanicOnError2(t *testing.T) {
 
 	t.Run("with non-nil error panics", func(t *testing.T) {
 		expectedErr := errors.New("test error")
-		assert.PanicsWithValue(t, expectedErr, func() {
+		ae := recoverAssertionError(func() {
 			PanicOnError2("value1", "value2", expectedErr)
 		})
+		assert.Same(t, expectedErr, ae.Err)
 	})
 }
 
@@ -95,9 +254,128 @@ func TestPanicOnError3(t *testing.T) {
 
 	t.Run("with non-nil error panics", func(t *testing.T) {
 		expectedErr := errors.New("test error")
-		assert.PanicsWithValue(t, expectedErr, func() {
+		ae := recoverAssertionError(func() {
 			PanicOnError3("value1", "value2", "value3", expectedErr)
 		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+}
+
+func TestPanicOnError4(t *testing.T) {
+	t.Run("with nil error returns values", func(t *testing.T) {
+		var (
+			v1 string
+			v2 int
+			v3 bool
+			v4 float64
+		)
+		assert.NotPanics(t, func() {
+			v1, v2, v3, v4 = PanicOnError4("a", 1, true, 3.14, nil)
+		})
+		assert.Equal(t, "a", v1)
+		assert.Equal(t, 1, v2)
+		assert.Equal(t, true, v3)
+		assert.Equal(t, 3.14, v4)
+	})
+
+	t.Run("with non-nil error panics", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		ae := recoverAssertionError(func() {
+			PanicOnError4("a", 1, true, 3.14, expectedErr)
+		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+}
+
+func TestPanicOnError5(t *testing.T) {
+	t.Run("with nil error returns values", func(t *testing.T) {
+		var (
+			v1 string
+			v2 int
+			v3 bool
+			v4 float64
+			v5 []byte
+		)
+		assert.NotPanics(t, func() {
+			v1, v2, v3, v4, v5 = PanicOnError5("a", 1, true, 3.14, []byte("b"), nil)
+		})
+		assert.Equal(t, "a", v1)
+		assert.Equal(t, 1, v2)
+		assert.Equal(t, true, v3)
+		assert.Equal(t, 3.14, v4)
+		assert.Equal(t, []byte("b"), v5)
+	})
+
+	t.Run("with non-nil error panics", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		ae := recoverAssertionError(func() {
+			PanicOnError5("a", 1, true, 3.14, []byte("b"), expectedErr)
+		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+}
+
+func TestPanicOnError6(t *testing.T) {
+	t.Run("with nil error returns values", func(t *testing.T) {
+		var (
+			v1 string
+			v2 int
+			v3 bool
+			v4 float64
+			v5 []byte
+			v6 error
+		)
+		assert.NotPanics(t, func() {
+			v1, v2, v3, v4, v5, v6 = PanicOnError6("a", 1, true, 3.14, []byte("b"), error(nil), nil)
+		})
+		assert.Equal(t, "a", v1)
+		assert.Equal(t, 1, v2)
+		assert.Equal(t, true, v3)
+		assert.Equal(t, 3.14, v4)
+		assert.Equal(t, []byte("b"), v5)
+		assert.Nil(t, v6)
+	})
+
+	t.Run("with non-nil error panics", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		ae := recoverAssertionError(func() {
+			PanicOnError6("a", 1, true, 3.14, []byte("b"), 'c', expectedErr)
+		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+}
+
+func TestPanicOnErrorAny(t *testing.T) {
+	t.Run("with nil error returns the leading values", func(t *testing.T) {
+		var values []any
+		assert.NotPanics(t, func() {
+			values = PanicOnErrorAny("a", 1, true, nil)
+		})
+		assert.Equal(t, []any{"a", 1, true}, values)
+	})
+
+	t.Run("with only a nil error returns no values", func(t *testing.T) {
+		assert.Empty(t, PanicOnErrorAny(nil))
+	})
+
+	t.Run("with non-nil error panics", func(t *testing.T) {
+		expectedErr := errors.New("test error")
+		ae := recoverAssertionError(func() {
+			PanicOnErrorAny("a", 1, expectedErr)
+		})
+		assert.Same(t, expectedErr, ae.Err)
+	})
+
+	t.Run("with last argument not an error panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "PanicOnErrorAny: last argument must be an error, got int", func() {
+			PanicOnErrorAny("a", 1)
+		})
+	})
+
+	t.Run("without arguments panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "PanicOnErrorAny: expected at least one argument", func() {
+			PanicOnErrorAny()
+		})
 	})
 }
 
@@ -135,6 +413,24 @@ func TestLogFatalOnError(t *testing.T) {
 		})
 	})
 
+	t.Run("LogFatalOnError0f", func(t *testing.T) {
+		t.Run("with nil error", func(t *testing.T) {
+			resetMocks()
+			LogFatalOnError0f(nil, "reading %s", "file.txt")
+			assert.False(t, fatalCalled)
+		})
+
+		t.Run("with non-nil error", func(t *testing.T) {
+			resetMocks()
+			err := errors.New("logfatal0f")
+			LogFatalOnError0f(err, "reading %s", "file.txt")
+			assert.True(t, fatalCalled)
+			fatalErr := fatalValue.(error)
+			assert.Equal(t, "reading file.txt: logfatal0f", fatalErr.Error())
+			assert.True(t, errors.Is(fatalErr, err))
+		})
+	})
+
 	t.Run("LogFatalOnError1", func(t *testing.T) {
 		t.Run("with nil error", func(t *testing.T) {
 			resetMocks()
@@ -189,3 +485,364 @@ func TestLogFatalOnError(t *testing.T) {
 		})
 	})
 }
+
+func TestSetFatalLogger(t *testing.T) {
+	// Save original state and restore after the test
+	originalLogFatal := logFatal
+	originalOsExit := osExit
+	defer func() {
+		logFatal = originalLogFatal
+		osExit = originalOsExit
+		SetFatalLogger(nil)
+	}()
+
+	var fatalCalled bool
+	logFatal = func(v ...any) {
+		fatalCalled = true
+	}
+
+	var exitCode int
+	osExit = func(code int) {
+		exitCode = code
+	}
+
+	var loggedMsg string
+	var loggedArgs []any
+	SetFatalLogger(func(msg string, args ...any) {
+		loggedMsg = msg
+		loggedArgs = args
+	})
+
+	t.Run("with nil error", func(t *testing.T) {
+		LogFatalOnError0(nil)
+		assert.Equal(t, "", loggedMsg)
+		assert.Equal(t, 0, exitCode)
+	})
+
+	t.Run("with non-nil error", func(t *testing.T) {
+		err := errors.New("fatal")
+		LogFatalOnError0(err)
+		assert.Equal(t, "fatal error", loggedMsg)
+		assert.Equal(t, []any{"err", err}, loggedArgs)
+		assert.Equal(t, 1, exitCode)
+		assert.False(t, fatalCalled)
+	})
+
+	t.Run("with nil logger restores the default", func(t *testing.T) {
+		SetFatalLogger(nil)
+		LogFatalOnError0(errors.New("fatal"))
+		assert.True(t, fatalCalled)
+	})
+}
+
+func TestLogFatalOnErrorCode(t *testing.T) {
+	// Save original state and restore after the test
+	originalLogFatal := logFatal
+	originalLogPrint := logPrint
+	originalOsExit := osExit
+	defer func() {
+		logFatal = originalLogFatal
+		logPrint = originalLogPrint
+		osExit = originalOsExit
+	}()
+
+	var fatalCalled bool
+	logFatal = func(v ...any) {
+		fatalCalled = true
+	}
+
+	var printValue any
+	logPrint = func(v ...any) {
+		printValue = v[0]
+	}
+
+	var exitCalled bool
+	var exitCode int
+	osExit = func(code int) {
+		exitCalled = true
+		exitCode = code
+	}
+
+	// Reset mocks before each subtest
+	resetMocks := func() {
+		fatalCalled = false
+		printValue = nil
+		exitCalled = false
+		exitCode = 0
+	}
+
+	t.Run("with nil error", func(t *testing.T) {
+		resetMocks()
+		LogFatalOnErrorCode(78, nil, "loading config")
+		assert.Nil(t, printValue)
+		assert.False(t, exitCalled)
+	})
+
+	t.Run("with non-nil error and msgs", func(t *testing.T) {
+		resetMocks()
+		err := errors.New("logfatalcode")
+		LogFatalOnErrorCode(78, err, "loading", "config")
+		assert.EqualError(t, printValue.(error), "loading config: logfatalcode")
+		assert.True(t, errors.Is(printValue.(error), err))
+		assert.True(t, exitCalled)
+		assert.Equal(t, 78, exitCode)
+		assert.False(t, fatalCalled)
+	})
+
+	t.Run("with non-nil error and no msgs", func(t *testing.T) {
+		resetMocks()
+		err := errors.New("logfatalcode")
+		LogFatalOnErrorCode(66, err)
+		assert.Equal(t, err, printValue)
+		assert.True(t, exitCalled)
+		assert.Equal(t, 66, exitCode)
+		assert.False(t, fatalCalled)
+	})
+}
+
+func TestSetFatalFormat(t *testing.T) {
+	// Save original state and restore after the test
+	originalLogFatal := logFatal
+	originalLogPrint := logPrint
+	originalOsExit := osExit
+	originalFatalWriter := fatalWriter
+	defer func() {
+		logFatal = originalLogFatal
+		logPrint = originalLogPrint
+		osExit = originalOsExit
+		fatalWriter = originalFatalWriter
+		SetFatalFormat(FormatText)
+	}()
+
+	var fatalValue any
+	logFatal = func(v ...any) {
+		fatalValue = v[0]
+	}
+
+	var printCalled bool
+	logPrint = func(v ...any) {
+		printCalled = true
+	}
+
+	var exitCode int
+	osExit = func(code int) {
+		exitCode = code
+	}
+
+	var buf bytes.Buffer
+	fatalWriter = &buf
+
+	// Reset mocks before each subtest
+	resetMocks := func() {
+		fatalValue = nil
+		printCalled = false
+		exitCode = 0
+		buf.Reset()
+	}
+
+	t.Run("with FormatText the output is unchanged", func(t *testing.T) {
+		resetMocks()
+		SetFatalFormat(FormatText)
+		err := errors.New("fatal")
+		LogFatalOnError0(err)
+		assert.Equal(t, err, fatalValue)
+		assert.Empty(t, buf.Bytes())
+	})
+
+	t.Run("with FormatJSON and nil error", func(t *testing.T) {
+		resetMocks()
+		SetFatalFormat(FormatJSON)
+		LogFatalOnError0(nil)
+		assert.Empty(t, buf.Bytes())
+		assert.Equal(t, 0, exitCode)
+	})
+
+	t.Run("with FormatJSON and non-nil error", func(t *testing.T) {
+		resetMocks()
+		SetFatalFormat(FormatJSON)
+		LogFatalOnError0(errors.New("fatal"))
+		var record map[string]string
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, map[string]string{
+			"level": "fatal",
+			"msg":   "fatal error",
+			"error": "fatal",
+		}, record)
+		assert.True(t, bytes.HasSuffix(buf.Bytes(), []byte("\n")))
+		assert.Equal(t, 1, exitCode)
+		assert.Nil(t, fatalValue)
+		assert.False(t, printCalled)
+	})
+
+	t.Run("with FormatJSON and msgs", func(t *testing.T) {
+		resetMocks()
+		SetFatalFormat(FormatJSON)
+		LogFatalOnErrorCode(78, errors.New("fatal"), "loading", "config")
+		var record map[string]string
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, map[string]string{
+			"level": "fatal",
+			"msg":   "loading config",
+			"error": "fatal",
+		}, record)
+		assert.Equal(t, 78, exitCode)
+		assert.False(t, printCalled)
+	})
+}
+
+// captureHandler is a [slog.Handler] recording the handled records.
+type captureHandler struct {
+	records []slog.Record
+}
+
+func (h *captureHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *captureHandler) Handle(_ context.Context, record slog.Record) error {
+	h.records = append(h.records, record)
+	return nil
+}
+
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *captureHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+func TestLogFatalOnErrorAttrs(t *testing.T) {
+	// Save original state and restore after the test
+	originalLogFatal := logFatal
+	originalOsExit := osExit
+	originalFatalWriter := fatalWriter
+	defer func() {
+		logFatal = originalLogFatal
+		osExit = originalOsExit
+		fatalWriter = originalFatalWriter
+		SetFatalLogger(nil)
+		SetFatalFormat(FormatText)
+	}()
+
+	var fatalValue any
+	logFatal = func(v ...any) {
+		fatalValue = v[0]
+	}
+
+	var exitCode int
+	osExit = func(code int) {
+		exitCode = code
+	}
+
+	// Reset mocks before each subtest
+	resetMocks := func() {
+		fatalValue = nil
+		exitCode = 0
+	}
+
+	t.Run("with nil error", func(t *testing.T) {
+		resetMocks()
+		LogFatalOnErrorAttrs(nil, "path", "/etc")
+		assert.Nil(t, fatalValue)
+		assert.Equal(t, 0, exitCode)
+	})
+
+	t.Run("with text format appends key=value text", func(t *testing.T) {
+		resetMocks()
+		err := errors.New("fatal")
+		LogFatalOnErrorAttrs(err, "path", "/etc", "attempt", 3)
+		assert.EqualError(t, fatalValue.(error), "fatal path=/etc attempt=3")
+		assert.ErrorIs(t, fatalValue.(error), err)
+	})
+
+	t.Run("with text format and odd attrs uses a sentinel", func(t *testing.T) {
+		resetMocks()
+		LogFatalOnErrorAttrs(errors.New("fatal"), "path", "/etc", "dangling")
+		assert.EqualError(t, fatalValue.(error), "fatal path=/etc dangling=!MISSING")
+	})
+
+	t.Run("with a structured logger emits structured fields", func(t *testing.T) {
+		resetMocks()
+		handler := &captureHandler{}
+		SetFatalLogger(slog.New(handler).Error)
+		defer SetFatalLogger(nil)
+		err := errors.New("fatal")
+		LogFatalOnErrorAttrs(err, "path", "/etc", "dangling")
+		if !assert.Len(t, handler.records, 1) {
+			return
+		}
+		record := handler.records[0]
+		assert.Equal(t, "fatal error", record.Message)
+		attrs := map[string]any{}
+		record.Attrs(func(attr slog.Attr) bool {
+			attrs[attr.Key] = attr.Value.Any()
+			return true
+		})
+		assert.Equal(t, map[string]any{"err": err, "path": "/etc", "dangling": "!MISSING"}, attrs)
+		assert.Equal(t, 1, exitCode)
+		assert.Nil(t, fatalValue)
+	})
+
+	t.Run("with FormatJSON emits additional fields", func(t *testing.T) {
+		resetMocks()
+		var buf bytes.Buffer
+		fatalWriter = &buf
+		SetFatalFormat(FormatJSON)
+		defer SetFatalFormat(FormatText)
+		LogFatalOnErrorAttrs(errors.New("fatal"), "path", "/etc", "attempt", 3, "cause", io.EOF, "ch", make(chan int))
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, "fatal", record["level"])
+		assert.Equal(t, "fatal", record["error"])
+		assert.Equal(t, "/etc", record["path"])
+		assert.Equal(t, float64(3), record["attempt"])
+		assert.Equal(t, "EOF", record["cause"])
+		assert.IsType(t, "", record["ch"])
+		assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte(`{"level":"fatal",`)))
+		assert.Equal(t, 1, exitCode)
+	})
+}
+
+func BenchmarkAssert(b *testing.B) {
+	b.ReportAllocs()
+	for idx := 0; b.Loop(); idx++ {
+		Assert(idx >= 0)
+	}
+}
+
+func BenchmarkAssertStatic(b *testing.B) {
+	b.ReportAllocs()
+	for idx := 0; b.Loop(); idx++ {
+		AssertStatic(idx >= 0)
+	}
+}
+
+func BenchmarkPanicOnError1(b *testing.B) {
+	b.ReportAllocs()
+	var sum int
+	for b.Loop() {
+		sum += PanicOnError1(17, nil)
+	}
+	_ = sum
+}
+
+func BenchmarkPanicOnError2(b *testing.B) {
+	b.ReportAllocs()
+	var sum int
+	for b.Loop() {
+		v1, v2 := PanicOnError2(17, 42, nil)
+		sum += v1 + v2
+	}
+	_ = sum
+}
+
+func BenchmarkPanicOnError3(b *testing.B) {
+	b.ReportAllocs()
+	var sum int
+	for b.Loop() {
+		v1, v2, v3 := PanicOnError3(17, 42, 11, nil)
+		sum += v1 + v2 + v3
+	}
+	_ = sum
+}
@@ -0,0 +1,394 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// AssertSorted panics unless s is sorted in ascending order. The value passed
+// to `panic()` is an [*AssertionError] whose message includes the index of the
+// first element that is less than its predecessor, e.g., `slice not sorted at
+// index 4`. Empty and single-element slices are always sorted.
+func AssertSorted[T cmp.Ordered](s []T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	for idx := 1; idx < len(s); idx++ {
+		if cmp.Less(s[idx], s[idx-1]) {
+			assertionFailed(fmt.Errorf("slice not sorted at index %d", idx))
+			return
+		}
+	}
+}
+
+// AssertSortedFunc is like [AssertSorted] but uses less to compare elements.
+func AssertSortedFunc[T any](s []T, less func(a, b T) bool) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	for idx := 1; idx < len(s); idx++ {
+		if less(s[idx], s[idx-1]) {
+			assertionFailed(fmt.Errorf("slice not sorted at index %d", idx))
+			return
+		}
+	}
+}
+
+// AssertMonotonic panics unless s is increasing. When strict is true, each
+// element must be greater than its predecessor, otherwise equal adjacent
+// elements are allowed. The value passed to `panic()` is an [*AssertionError]
+// whose message includes the index and the values of the first violation,
+// e.g., `non-monotonic at index 3: 5 then 4`.
+//
+// Unlike [AssertSorted], this function can reject duplicates, as needed
+// to validate, e.g., the sequence numbers of ingested events.
+func AssertMonotonic[T cmp.Ordered](s []T, strict bool) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	for idx := 1; idx < len(s); idx++ {
+		if c := cmp.Compare(s[idx-1], s[idx]); c > 0 || (strict && c == 0) {
+			assertionFailed(fmt.Errorf("non-monotonic at index %d: %v then %v", idx, s[idx-1], s[idx]))
+			return
+		}
+	}
+}
+
+// AssertStepAtLeast panics unless each element of s exceeds its predecessor by
+// at least minStep. The value passed to `panic()` is an [*AssertionError] whose
+// message includes the index of the first violation and the actual delta, e.g.,
+// `step below 5 at index 3: delta 2`. A decrease yields a negative delta, even
+// for unsigned types. A negative minStep allows decreases by at most -minStep.
+// Empty and single-element slices always pass.
+//
+// This is stricter than [AssertMonotonic], e.g., to check that samples of
+// a counter increase at a minimum rate.
+func AssertStepAtLeast[T Number](s []T, minStep T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	isFloat := T(1)/2 != 0
+	for idx := 1; idx < len(s); idx++ {
+		prev, cur := s[idx-1], s[idx]
+		if isFloat {
+			// Floating point numbers do not overflow but lose precision, e.g.,
+			// 1e20 + 1 equals 1e20, so we compare the delta directly. Negating
+			// the comparison also rejects NaN.
+			if delta := cur - prev; !(delta >= minStep) {
+				assertionFailed(fmt.Errorf("step below %v at index %d: delta %v", minStep, idx, delta))
+				return
+			}
+			continue
+		}
+		if !integerStepAtLeast(prev, cur, minStep) {
+			if cur < prev {
+				assertionFailed(fmt.Errorf("step below %v at index %d: delta -%s", minStep, idx, formatDiff(prev, cur)))
+				return
+			}
+			// Here cur - prev < minStep, hence it does not overflow.
+			assertionFailed(fmt.Errorf("step below %v at index %d: delta %v", minStep, idx, cur-prev))
+			return
+		}
+	}
+}
+
+// integerStepAtLeast returns whether cur - prev >= minStep for integer types.
+// It compares prev + minStep with cur because cur - prev overflows for signed
+// integers with a wide range. If prev + minStep overflows, then cur cannot be
+// large enough. If it underflows, then cur cannot be small enough.
+func integerStepAtLeast[T Number](prev, cur, minStep T) bool {
+	next := prev + minStep
+	if minStep >= 0 {
+		return next >= prev && next <= cur
+	}
+	return next > prev || next <= cur
+}
+
+// formatDiff formats a - b, where a > b, such that the result is correct even
+// when the difference does not fit into T, e.g., 100 - (-100) with int8.
+func formatDiff[T Number](a, b T) string {
+	if T(1)/2 != 0 { // floating point
+		return fmt.Sprint(a - b)
+	}
+	// Converting to int64 and then to uint64 preserves the value modulo 2^64
+	// for any integer type, hence the difference modulo 2^64 is exact.
+	return strconv.FormatUint(uint64(int64(a))-uint64(int64(b)), 10)
+}
+
+// AssertNonOverlappingRanges panics if any of the half-open [start, end)
+// ranges overlaps with another range or has start greater than end. The value
+// passed to `panic()` is an [*AssertionError] describing the first invalid
+// range, e.g., `invalid range [9,5)`, or the first overlap after sorting the
+// ranges by start, e.g., `ranges overlap: [3,7) and [5,9)`. Since the ranges
+// are half-open, adjacent ranges such as [3,5) and [5,9) do not overlap.
+func AssertNonOverlappingRanges[T cmp.Ordered](ranges [][2]T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	for _, r := range ranges {
+		if cmp.Less(r[1], r[0]) {
+			assertionFailed(fmt.Errorf("invalid range [%v,%v)", r[0], r[1]))
+			return
+		}
+	}
+	sorted := slices.Clone(ranges)
+	slices.SortFunc(sorted, func(a, b [2]T) int {
+		return cmp.Compare(a[0], b[0])
+	})
+	for idx := 1; idx < len(sorted); idx++ {
+		prev, cur := sorted[idx-1], sorted[idx]
+		if cmp.Less(cur[0], prev[1]) {
+			assertionFailed(fmt.Errorf("ranges overlap: [%v,%v) and [%v,%v)", prev[0], prev[1], cur[0], cur[1]))
+			return
+		}
+	}
+}
+
+// AssertUnique panics if s contains duplicate elements. The value passed to
+// `panic()` is an [*AssertionError] whose message includes the first repeated
+// value and the index where it repeats, e.g., `duplicate value 7 at index 3`.
+func AssertUnique[T comparable](s []T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	seen := make(map[T]struct{}, len(s))
+	for idx, v := range s {
+		if _, found := seen[v]; found {
+			assertionFailed(fmt.Errorf("duplicate value %v at index %d", v, idx))
+			return
+		}
+		seen[v] = struct{}{}
+	}
+}
+
+// AssertDisjoint panics if a and b have elements in common. The value passed
+// to `panic()` is an [*AssertionError] whose message includes the first value
+// of b that also occurs in a, e.g., `slices overlap at value 7`. An empty
+// slice is disjoint from any slice.
+func AssertDisjoint[T comparable](a, b []T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	seen := make(map[T]struct{}, len(a))
+	for _, v := range a {
+		seen[v] = struct{}{}
+	}
+	for _, v := range b {
+		if _, found := seen[v]; found {
+			assertionFailed(fmt.Errorf("slices overlap at value %v", v))
+			return
+		}
+	}
+}
+
+// AssertSubset panics unless every element of sub occurs in super. The value
+// passed to `panic()` is an [*AssertionError] whose message includes the first
+// missing value, e.g., `value 7 in subset not found in superset`. An empty
+// slice is a subset of any slice.
+func AssertSubset[T comparable](sub, super []T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	seen := make(map[T]struct{}, len(super))
+	for _, v := range super {
+		seen[v] = struct{}{}
+	}
+	for _, v := range sub {
+		if _, found := seen[v]; !found {
+			assertionFailed(fmt.Errorf("value %v in subset not found in superset", v))
+			return
+		}
+	}
+}
+
+// AssertAll panics unless pred returns true for every element of items. The
+// value passed to `panic()` is an [*AssertionError] whose message includes the
+// index of the first failing element, e.g., `predicate failed at index 3`.
+// An empty slice always satisfies this assertion.
+func AssertAll[T any](items []T, pred func(T) bool) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	for idx, v := range items {
+		if !pred(v) {
+			assertionFailed(fmt.Errorf("predicate failed at index %d", idx))
+			return
+		}
+	}
+}
+
+// AssertAny panics unless pred returns true for at least one element of items.
+// The value passed to `panic()` is an [*AssertionError] whose message is `no
+// element satisfied predicate`. An empty slice never satisfies this assertion.
+func AssertAny[T any](items []T, pred func(T) bool) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !slices.ContainsFunc(items, pred) {
+		assertionFailed(errors.New("no element satisfied predicate"))
+	}
+}
+
+// AssertEqualSlice panics unless got and want have the same length and equal
+// elements. The value passed to `panic()` is an [*AssertionError] describing
+// the first difference, e.g., `length mismatch: 3 vs 4` or `slices differ
+// at index 2: got 5, want 7`. A nil slice is equal to an empty slice.
+func AssertEqualSlice[T comparable](got, want []T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if len(got) != len(want) {
+		assertionFailed(fmt.Errorf("length mismatch: %d vs %d", len(got), len(want)))
+		return
+	}
+	for idx := range got {
+		if got[idx] != want[idx] {
+			assertionFailed(fmt.Errorf("slices differ at index %d: got %v, want %v", idx, got[idx], want[idx]))
+			return
+		}
+	}
+}
+
+// AssertElementsMatch panics unless got and want contain the same elements,
+// with the same multiplicity, regardless of their order. The value passed to
+// `panic()` is an [*AssertionError] listing the elements of want missing from
+// got and the extra elements of got, e.g., `missing: [a], extra: [b]`. Each
+// list preserves the order of the slice it comes from.
+func AssertElementsMatch[T comparable](got, want []T) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	counts := make(map[T]int, len(got))
+	for _, v := range got {
+		counts[v]++
+	}
+	var missing, extra []T
+	for _, v := range want {
+		if counts[v] <= 0 {
+			missing = append(missing, v)
+			continue
+		}
+		counts[v]--
+	}
+	for _, v := range got {
+		if counts[v] > 0 {
+			extra = append(extra, v)
+			counts[v]--
+		}
+	}
+	if len(missing) > 0 || len(extra) > 0 {
+		assertionFailed(fmt.Errorf("missing: %v, extra: %v", missing, extra))
+	}
+}
+
+// AssertEqualMap panics unless got and want contain the same keys mapping
+// to equal values. The value passed to `panic()` is an [*AssertionError]
+// describing a differing or missing key, e.g., `maps differ at key a: got 1,
+// want 2` or `key b missing from got`. A nil map is equal to an empty map.
+//
+// When several keys differ, the message describes the smallest one, such
+// that the message does not depend on the map iteration order.
+func AssertEqualMap[K cmp.Ordered, V comparable](got, want map[K]V) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if err := diffMaps(got, want, cmp.Compare[K]); err != nil {
+		assertionFailed(err)
+	}
+}
+
+// AssertEqualMapFunc is like [AssertEqualMap] but supports keys that are not
+// ordered, using compare to select the smallest differing key. The compare
+// function must return a negative number when a < b, a positive number
+// when a > b, and zero otherwise, like [cmp.Compare].
+func AssertEqualMapFunc[K, V comparable](got, want map[K]V, compare func(a, b K) int) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if err := diffMaps(got, want, compare); err != nil {
+		assertionFailed(err)
+	}
+}
+
+// AssertKeysEqual panics unless got and want contain the same keys, ignoring
+// the values. The value passed to `panic()` is an [*AssertionError] listing, in
+// sorted order, the keys of want missing from got and the extra keys of got,
+// e.g., `missing keys: [x], extra keys: [y]`. A nil map is equal to an empty map.
+func AssertKeysEqual[K cmp.Ordered, V any](got, want map[K]V) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if err := diffKeys(got, want, cmp.Compare[K]); err != nil {
+		assertionFailed(err)
+	}
+}
+
+// AssertKeysEqualFunc is like [AssertKeysEqual] but supports keys that are not
+// ordered, using compare to sort the missing and extra keys. The compare
+// function must return a negative number when a < b, a positive number
+// when a > b, and zero otherwise, like [cmp.Compare].
+func AssertKeysEqualFunc[K comparable, V any](got, want map[K]V, compare func(a, b K) int) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if err := diffKeys(got, want, compare); err != nil {
+		assertionFailed(err)
+	}
+}
+
+// diffKeys returns an error listing, sorted according to compare, the keys
+// of want missing from got and the extra keys of got, or nil if there are none.
+func diffKeys[K comparable, V any](got, want map[K]V, compare func(a, b K) int) error {
+	var missing, extra []K
+	for key := range want {
+		if _, found := got[key]; !found {
+			missing = append(missing, key)
+		}
+	}
+	for key := range got {
+		if _, found := want[key]; !found {
+			extra = append(extra, key)
+		}
+	}
+	if len(missing) == 0 && len(extra) == 0 {
+		return nil
+	}
+	slices.SortFunc(missing, compare)
+	slices.SortFunc(extra, compare)
+	return fmt.Errorf("missing keys: %v, extra keys: %v", missing, extra)
+}
+
+// diffMaps returns an error describing the smallest key, according to
+// compare, for which got and want differ, or nil if they are equal.
+func diffMaps[K, V comparable](got, want map[K]V, compare func(a, b K) int) error {
+	var keys []K
+	for key, wantValue := range want {
+		if gotValue, found := got[key]; !found || gotValue != wantValue {
+			keys = append(keys, key)
+		}
+	}
+	for key := range got {
+		if _, found := want[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) <= 0 {
+		return nil
+	}
+	key := slices.MinFunc(keys, compare)
+	gotValue, inGot := got[key]
+	wantValue, inWant := want[key]
+	switch {
+	case !inGot:
+		return fmt.Errorf("key %v missing from got", key)
+	case !inWant:
+		return fmt.Errorf("key %v missing from want", key)
+	default:
+		return fmt.Errorf("maps differ at key %v: got %v, want %v", key, gotValue, wantValue)
+	}
+}
@@ -0,0 +1,460 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"cmp"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertSorted(t *testing.T) {
+	t.Run("with sorted slices does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertSorted([]int{})
+			AssertSorted([]int{1})
+			AssertSorted([]int{1, 1, 2, 3, 5})
+			AssertSorted([]string{"a", "b", "c"})
+		})
+	})
+
+	t.Run("with unsorted slice panics reporting the index", func(t *testing.T) {
+		assert.PanicsWithError(t, "slice not sorted at index 4", func() {
+			AssertSorted([]int{1, 2, 3, 5, 4, 0})
+		})
+	})
+}
+
+func TestAssertSortedFunc(t *testing.T) {
+	greater := func(a, b int) bool {
+		return a > b
+	}
+
+	t.Run("with sorted slices does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertSortedFunc([]int{}, greater)
+			AssertSortedFunc([]int{1}, greater)
+			AssertSortedFunc([]int{5, 3, 3, 1}, greater)
+		})
+	})
+
+	t.Run("with unsorted slice panics reporting the index", func(t *testing.T) {
+		assert.PanicsWithError(t, "slice not sorted at index 2", func() {
+			AssertSortedFunc([]int{5, 3, 4, 1}, greater)
+		})
+	})
+}
+
+func TestAssertMonotonic(t *testing.T) {
+	t.Run("with strictly increasing elements does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertMonotonic([]int{}, true)
+			AssertMonotonic([]int{1}, true)
+			AssertMonotonic([]int{1, 2, 5, 9}, true)
+			AssertMonotonic([]int{1, 2, 5, 9}, false)
+		})
+	})
+
+	t.Run("with duplicates and strict false does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertMonotonic([]int{1, 2, 2, 3}, false)
+		})
+	})
+
+	t.Run("with duplicates and strict true panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "non-monotonic at index 2: 2 then 2", func() {
+			AssertMonotonic([]int{1, 2, 2, 3}, true)
+		})
+	})
+
+	t.Run("with a decreasing pair panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "non-monotonic at index 3: 5 then 4", func() {
+			AssertMonotonic([]int{1, 2, 5, 4}, false)
+		})
+	})
+}
+
+func TestAssertStepAtLeast(t *testing.T) {
+	t.Run("with steps meeting the minimum does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertStepAtLeast([]int{0, 5, 10, 20}, 5)
+			AssertStepAtLeast([]float64{0, 0.5, 1.5}, 0.5)
+		})
+	})
+
+	t.Run("with empty and single-element slices does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertStepAtLeast([]int{}, 5)
+			AssertStepAtLeast([]int{17}, 5)
+		})
+	})
+
+	t.Run("with a too small step in the middle panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "step below 5 at index 3: delta 2", func() {
+			AssertStepAtLeast([]int{0, 5, 10, 12, 20}, 5)
+		})
+	})
+
+	t.Run("with an unsigned decrease reports a negative delta", func(t *testing.T) {
+		assert.PanicsWithError(t, "step below 1 at index 1: delta -2", func() {
+			AssertStepAtLeast([]uint{5, 3}, 1)
+		})
+	})
+
+	t.Run("with int8 values spanning the whole range does not overflow", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertStepAtLeast([]int8{-100, 100}, 5)
+			AssertStepAtLeast([]int8{math.MinInt8, math.MaxInt8}, math.MaxInt8)
+			AssertStepAtLeast([]int8{-100, 100}, -5)
+		})
+		assert.PanicsWithError(t, "step below 5 at index 1: delta 1", func() {
+			AssertStepAtLeast([]int8{126, 127}, 5)
+		})
+		assert.PanicsWithError(t, "step below 5 at index 1: delta -200", func() {
+			AssertStepAtLeast([]int8{100, -100}, 5)
+		})
+	})
+
+	t.Run("with a uint64 decrease spanning the whole range reports the delta", func(t *testing.T) {
+		assert.PanicsWithError(t, "step below 1 at index 1: delta -18446744073709551615", func() {
+			AssertStepAtLeast([]uint64{math.MaxUint64, 0}, 1)
+		})
+	})
+
+	t.Run("with a float decrease reports a negative delta", func(t *testing.T) {
+		assert.PanicsWithError(t, "step below 0.5 at index 1: delta -0.25", func() {
+			AssertStepAtLeast([]float64{1, 0.75}, 0.5)
+		})
+	})
+
+	t.Run("with a negative minimum allows small decreases", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertStepAtLeast([]int{10, 8, 3}, -5)
+			AssertStepAtLeast([]int8{-100, math.MinInt8}, -100)
+			AssertStepAtLeast([]float64{10, 8}, -5)
+		})
+		assert.PanicsWithError(t, "step below -5 at index 1: delta -6", func() {
+			AssertStepAtLeast([]int{10, 4}, -5)
+		})
+		assert.PanicsWithError(t, "step below -100 at index 1: delta -227", func() {
+			AssertStepAtLeast([]int8{math.MaxInt8, -100}, -100)
+		})
+	})
+
+	t.Run("with large floats does not lose the delta", func(t *testing.T) {
+		assert.PanicsWithError(t, "step below 1 at index 1: delta 0", func() {
+			AssertStepAtLeast([]float64{1e20, 1e20}, 1)
+		})
+	})
+}
+
+func TestAssertNonOverlappingRanges(t *testing.T) {
+	t.Run("with disjoint ranges does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertNonOverlappingRanges[int](nil)
+			AssertNonOverlappingRanges([][2]int{{10, 20}, {0, 5}, {6, 9}})
+		})
+	})
+
+	t.Run("with adjacent ranges does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertNonOverlappingRanges([][2]int{{5, 9}, {3, 5}})
+		})
+	})
+
+	t.Run("with overlapping ranges panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "ranges overlap: [3,7) and [5,9)", func() {
+			AssertNonOverlappingRanges([][2]int{{10, 12}, {5, 9}, {3, 7}})
+		})
+	})
+
+	t.Run("with a reversed range panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "invalid range [9,5)", func() {
+			AssertNonOverlappingRanges([][2]int{{0, 1}, {9, 5}})
+		})
+	})
+
+	t.Run("does not modify the input", func(t *testing.T) {
+		ranges := [][2]int{{5, 9}, {0, 5}}
+		AssertNonOverlappingRanges(ranges)
+		assert.Equal(t, [][2]int{{5, 9}, {0, 5}}, ranges)
+	})
+}
+
+func TestAssertUnique(t *testing.T) {
+	t.Run("with unique elements does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertUnique([]int{})
+			AssertUnique([]int{1, 2, 3})
+			AssertUnique([]string{"a", "b"})
+		})
+	})
+
+	t.Run("with duplicate near the start panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "duplicate value 1 at index 1", func() {
+			AssertUnique([]int{1, 1, 2, 3})
+		})
+	})
+
+	t.Run("with duplicate at the end panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "duplicate value a at index 3", func() {
+			AssertUnique([]string{"a", "b", "c", "a"})
+		})
+	})
+}
+
+func TestAssertDisjoint(t *testing.T) {
+	t.Run("with disjoint slices does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertDisjoint([]int{1, 2}, []int{3, 4})
+			AssertDisjoint([]int{}, []int{1})
+			AssertDisjoint([]int{1}, nil)
+			AssertDisjoint[int](nil, nil)
+		})
+	})
+
+	t.Run("with overlapping slices panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "slices overlap at value 3", func() {
+			AssertDisjoint([]int{1, 2, 3}, []int{4, 3, 2})
+		})
+	})
+}
+
+func TestAssertSubset(t *testing.T) {
+	t.Run("with a valid subset does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertSubset([]string{"b", "a", "b"}, []string{"a", "b", "c"})
+			AssertSubset([]string{}, []string{"a"})
+			AssertSubset[string](nil, nil)
+		})
+	})
+
+	t.Run("with a value missing from the superset panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "value d in subset not found in superset", func() {
+			AssertSubset([]string{"a", "d", "e"}, []string{"a", "b", "c"})
+		})
+	})
+
+	t.Run("with an empty superset panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "value a in subset not found in superset", func() {
+			AssertSubset([]string{"a"}, nil)
+		})
+	})
+}
+
+func TestAssertAll(t *testing.T) {
+	isPositive := func(v int) bool { return v > 0 }
+
+	t.Run("with all elements satisfying the predicate does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertAll([]int{1, 2, 3}, isPositive)
+		})
+	})
+
+	t.Run("with an empty slice does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertAll(nil, isPositive)
+		})
+	})
+
+	t.Run("with a failing element panics with its index", func(t *testing.T) {
+		assert.PanicsWithError(t, "predicate failed at index 3", func() {
+			AssertAll([]int{1, 2, 3, -4, -5}, isPositive)
+		})
+	})
+}
+
+func TestAssertAny(t *testing.T) {
+	isPositive := func(v int) bool { return v > 0 }
+
+	t.Run("with one element satisfying the predicate does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertAny([]int{-1, 0, 3}, isPositive)
+		})
+	})
+
+	t.Run("with no element satisfying the predicate panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "no element satisfied predicate", func() {
+			AssertAny([]int{-1, 0}, isPositive)
+		})
+	})
+
+	t.Run("with an empty slice panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "no element satisfied predicate", func() {
+			AssertAny(nil, isPositive)
+		})
+	})
+}
+
+func TestAssertEqualSlice(t *testing.T) {
+	t.Run("with equal inputs does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertEqualSlice([]int{1, 2, 3}, []int{1, 2, 3})
+			AssertEqualSlice([]int(nil), []int{})
+		})
+	})
+
+	t.Run("with length mismatch panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "length mismatch: 3 vs 4", func() {
+			AssertEqualSlice([]int{1, 2, 3}, []int{1, 2, 3, 4})
+		})
+	})
+
+	t.Run("with value mismatch panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "slices differ at index 2: got 5, want 7", func() {
+			AssertEqualSlice([]int{1, 2, 5}, []int{1, 2, 7})
+		})
+	})
+}
+
+func TestAssertElementsMatch(t *testing.T) {
+	t.Run("with equal multisets in different orders does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertElementsMatch([]string{"a", "b", "a"}, []string{"a", "a", "b"})
+			AssertElementsMatch([]string{}, nil)
+		})
+	})
+
+	t.Run("with differing counts of a duplicate panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "missing: [b], extra: [a]", func() {
+			AssertElementsMatch([]string{"a", "a", "b"}, []string{"a", "b", "b"})
+		})
+	})
+
+	t.Run("with disjoint slices panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "missing: [c d], extra: [a b]", func() {
+			AssertElementsMatch([]string{"a", "b"}, []string{"c", "d"})
+		})
+	})
+
+	t.Run("with only extra elements panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "missing: [], extra: [2]", func() {
+			AssertElementsMatch([]int{1, 2}, []int{1})
+		})
+	})
+}
+
+func TestAssertEqualMap(t *testing.T) {
+	t.Run("with equal inputs does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertEqualMap(map[string]int{"a": 1, "b": 2}, map[string]int{"b": 2, "a": 1})
+			AssertEqualMap(map[string]int(nil), map[string]int{})
+		})
+	})
+
+	t.Run("with value mismatch panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "maps differ at key a: got 1, want 2", func() {
+			AssertEqualMap(map[string]int{"a": 1}, map[string]int{"a": 2})
+		})
+	})
+
+	t.Run("with key missing from got panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "key b missing from got", func() {
+			AssertEqualMap(map[string]int{"a": 1}, map[string]int{"a": 1, "b": 2})
+		})
+	})
+
+	t.Run("with key missing from want panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "key b missing from want", func() {
+			AssertEqualMap(map[string]int{"a": 1, "b": 2}, map[string]int{"a": 1})
+		})
+	})
+
+	t.Run("with several differences reports the smallest key", func(t *testing.T) {
+		got := map[string]int{"a": 1, "c": 3, "d": 5, "f": 6}
+		want := map[string]int{"a": 1, "b": 2, "d": 4, "e": 5}
+		// Repeat to make sure the message does not depend on the iteration order
+		for range 32 {
+			assert.PanicsWithError(t, "key b missing from got", func() {
+				AssertEqualMap(got, want)
+			})
+			assert.PanicsWithError(t, "key c missing from want", func() {
+				AssertEqualMap(got, map[string]int{"a": 1, "d": 4, "e": 5})
+			})
+			assert.PanicsWithError(t, "maps differ at key d: got 5, want 4", func() {
+				AssertEqualMap(map[string]int{"a": 1, "d": 5, "f": 6}, map[string]int{"a": 1, "d": 4, "e": 5})
+			})
+		}
+	})
+}
+
+func TestAssertEqualMapFunc(t *testing.T) {
+	compareStructs := func(a, b comparableStruct) int {
+		return cmp.Compare(a.Value, b.Value)
+	}
+
+	t.Run("with equal inputs does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertEqualMapFunc(
+				map[comparableStruct]int{{Name: "a", Value: 1}: 1},
+				map[comparableStruct]int{{Name: "a", Value: 1}: 1},
+				compareStructs,
+			)
+		})
+	})
+
+	t.Run("with several differences reports the smallest key", func(t *testing.T) {
+		got := map[comparableStruct]int{{Name: "x", Value: 3}: 1, {Name: "y", Value: 2}: 1}
+		want := map[comparableStruct]int{{Name: "x", Value: 3}: 2, {Name: "y", Value: 2}: 2}
+		for range 32 {
+			assert.PanicsWithError(t, "maps differ at key {y 2}: got 1, want 2", func() {
+				AssertEqualMapFunc(got, want, compareStructs)
+			})
+		}
+	})
+}
+
+func TestAssertKeysEqual(t *testing.T) {
+	t.Run("with identical keys and differing values does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertKeysEqual(map[string]int{"a": 1, "b": 2}, map[string]int{"a": 3, "b": 4})
+			AssertKeysEqual(map[string]int(nil), map[string]int{})
+		})
+	})
+
+	t.Run("with a missing key panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "missing keys: [b], extra keys: []", func() {
+			AssertKeysEqual(map[string]int{"a": 1}, map[string]int{"a": 1, "b": 2})
+		})
+	})
+
+	t.Run("with an extra key panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "missing keys: [], extra keys: [b]", func() {
+			AssertKeysEqual(map[string]int{"a": 1, "b": 2}, map[string]int{"a": 1})
+		})
+	})
+
+	t.Run("with missing and extra keys panics listing them in order", func(t *testing.T) {
+		assert.PanicsWithError(t, "missing keys: [x z], extra keys: [b c]", func() {
+			AssertKeysEqual(map[string]int{"a": 1, "c": 1, "b": 1}, map[string]int{"a": 1, "z": 1, "x": 1})
+		})
+	})
+}
+
+func TestAssertKeysEqualFunc(t *testing.T) {
+	compareStructs := func(a, b comparableStruct) int {
+		return cmp.Compare(a.Value, b.Value)
+	}
+
+	t.Run("with identical keys and differing values does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertKeysEqualFunc(
+				map[comparableStruct]int{{Name: "a", Value: 1}: 1},
+				map[comparableStruct]int{{Name: "a", Value: 1}: 2},
+				compareStructs,
+			)
+		})
+	})
+
+	t.Run("with missing and extra keys panics listing them in order", func(t *testing.T) {
+		got := map[comparableStruct]int{{Name: "c", Value: 3}: 1, {Name: "b", Value: 2}: 1}
+		want := map[comparableStruct]int{{Name: "z", Value: 5}: 1, {Name: "x", Value: 4}: 1}
+		for range 32 {
+			assert.PanicsWithError(t, "missing keys: [{x 4} {z 5}], extra keys: [{b 2} {c 3}]", func() {
+				AssertKeysEqualFunc(got, want, compareStructs)
+			})
+		}
+	})
+}
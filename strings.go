@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxShownRunes is the maximum number of runes of a string value that
+// the string assertions include in their messages.
+const maxShownRunes = 40
+
+// AssertHasPrefix panics unless s starts with prefix. The value passed to
+// `panic()` is an [*AssertionError] whose message includes both strings,
+// e.g., `expected prefix "HTTP/", got "SSH-2.0-OpenSSH_9.6"`. When s is
+// longer than 40 runes, the message only includes its beginning followed
+// by an ellipsis, to keep the message readable.
+func AssertHasPrefix(s, prefix string) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !strings.HasPrefix(s, prefix) {
+		assertionFailed(fmt.Errorf("expected prefix %q, got %q", prefix, truncateHead(s, maxShownRunes)))
+	}
+}
+
+// AssertHasSuffix panics unless s ends with suffix. The value passed to
+// `panic()` is an [*AssertionError] whose message includes both strings,
+// e.g., `expected suffix "\r\n", got "GET / HTTP/1.1"`. When s is longer
+// than 40 runes, the message only includes an ellipsis followed by its
+// end, to keep the message readable.
+func AssertHasSuffix(s, suffix string) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !strings.HasSuffix(s, suffix) {
+		assertionFailed(fmt.Errorf("expected suffix %q, got %q", suffix, truncateTail(s)))
+	}
+}
+
+// AssertRegexpMatch panics unless s matches re. The value passed to `panic()`
+// is an [*AssertionError] whose message includes both s and the pattern, e.g.,
+// `string "req-17" does not match /^[a-z]+-[0-9a-f]{8}$/`.
+func AssertRegexpMatch(re *regexp.Regexp, s string) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !re.MatchString(s) {
+		assertionFailed(fmt.Errorf("string %q does not match /%s/", s, re))
+	}
+}
+
+// AssertRegexpMatchString is like [AssertRegexpMatch] but compiles pattern
+// using [MustCompile], thus panicking with the compile error if pattern is
+// not valid. Prefer [AssertRegexpMatch] with a precompiled [*regexp.Regexp]
+// in hot code paths, since this function compiles pattern on every call.
+func AssertRegexpMatchString(pattern, s string) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	re, err := regexp.Compile(pattern)
+	panicOnError(1, err)
+	if !re.MatchString(s) {
+		assertionFailed(fmt.Errorf("string %q does not match /%s/", s, re))
+	}
+}
+
+// truncateHead returns the first limit runes of s followed by an
+// ellipsis, or s itself if it is not longer than limit runes.
+func truncateHead(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit]) + "..."
+}
+
+// truncateTail returns an ellipsis followed by the last [maxShownRunes] runes
+// of s, or s itself if it is not longer than [maxShownRunes].
+func truncateTail(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxShownRunes {
+		return s
+	}
+	return "..." + string(runes[len(runes)-maxShownRunes:])
+}
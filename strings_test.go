@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertHasPrefix(t *testing.T) {
+	t.Run("with matching prefix does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertHasPrefix("HTTP/1.1 200 OK", "HTTP/")
+			AssertHasPrefix("HTTP/1.1 200 OK", "")
+		})
+	})
+
+	t.Run("with non-matching prefix panics", func(t *testing.T) {
+		assert.PanicsWithError(t, `expected prefix "HTTP/", got "SSH-2.0"`, func() {
+			AssertHasPrefix("SSH-2.0", "HTTP/")
+		})
+	})
+
+	t.Run("with an overlong value truncates the message", func(t *testing.T) {
+		s := strings.Repeat("é", 40) + "tail"
+		expected := `expected prefix "HTTP/", got "` + strings.Repeat("é", 40) + `..."`
+		assert.PanicsWithError(t, expected, func() {
+			AssertHasPrefix(s, "HTTP/")
+		})
+	})
+}
+
+func TestAssertHasSuffix(t *testing.T) {
+	t.Run("with matching suffix does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertHasSuffix("GET / HTTP/1.1\r\n", "\r\n")
+			AssertHasSuffix("GET / HTTP/1.1\r\n", "")
+		})
+	})
+
+	t.Run("with non-matching suffix panics", func(t *testing.T) {
+		assert.PanicsWithError(t, `expected suffix "\r\n", got "GET / HTTP/1.1"`, func() {
+			AssertHasSuffix("GET / HTTP/1.1", "\r\n")
+		})
+	})
+
+	t.Run("with an overlong value truncates the message", func(t *testing.T) {
+		s := "head" + strings.Repeat("é", 40)
+		expected := `expected suffix "\r\n", got "...` + strings.Repeat("é", 40) + `"`
+		assert.PanicsWithError(t, expected, func() {
+			AssertHasSuffix(s, "\r\n")
+		})
+	})
+}
+
+func TestAssertRegexpMatch(t *testing.T) {
+	re := regexp.MustCompile(`^req-[0-9a-f]{8}$`)
+
+	t.Run("with matching string does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertRegexpMatch(re, "req-0123abcd")
+		})
+	})
+
+	t.Run("with non-matching string panics", func(t *testing.T) {
+		assert.PanicsWithError(t, `string "req-17" does not match /^req-[0-9a-f]{8}$/`, func() {
+			AssertRegexpMatch(re, "req-17")
+		})
+	})
+}
+
+func TestAssertRegexpMatchString(t *testing.T) {
+	t.Run("with matching string does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertRegexpMatchString(`^req-[0-9a-f]{8}$`, "req-0123abcd")
+		})
+	})
+
+	t.Run("with non-matching string panics", func(t *testing.T) {
+		assert.PanicsWithError(t, `string "req-17" does not match /^req-[0-9a-f]{8}$/`, func() {
+			AssertRegexpMatchString(`^req-[0-9a-f]{8}$`, "req-17")
+		})
+	})
+
+	t.Run("with invalid pattern panics with the compile error", func(t *testing.T) {
+		assert.PanicsWithError(t, "error parsing regexp: missing closing ]: `[`", func() {
+			AssertRegexpMatchString(`[`, "req-17")
+		})
+	})
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SupervisorOptions contains the options for [Supervise]. The zero value
+// is ready to use and restarts without limits using the default backoff.
+type SupervisorOptions struct {
+	// Logger is called with each panic converted to an error using
+	// [NormalizeRecovered] and, when giving up after MaxRestarts restarts,
+	// with an error wrapping the last panic, e.g., `giving up after 3
+	// restarts: <err>`. If nil, we log using [log.Print]. Logger is
+	// called from the goroutine started by [Supervise].
+	Logger func(err error)
+
+	// MaxRestarts is the maximum number of restarts. A zero or
+	// negative value means that there is no limit.
+	MaxRestarts int
+
+	// InitialBackoff is the delay before the first restart, which doubles
+	// at each following restart. If zero, we use 100 milliseconds.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between restarts. If zero,
+	// we use 30 seconds.
+	MaxBackoff time.Duration
+}
+
+// Supervise calls fn with ctx in a background goroutine and, when fn panics,
+// logs the panic and calls fn again after an exponential backoff, such that
+// a long-running loop using the PanicOnErrorN family survives transient
+// failures. The goroutine stops when:
+//
+//   - fn returns normally;
+//
+//   - ctx is done;
+//
+//   - fn panics after [SupervisorOptions.MaxRestarts] restarts, in which case
+//     it logs that it is giving up using [SupervisorOptions.Logger].
+//
+// For example:
+//
+//	runtimex.Supervise(ctx, worker.Loop, runtimex.SupervisorOptions{MaxRestarts: 10})
+func Supervise(ctx context.Context, fn func(context.Context), opts SupervisorOptions) {
+	go supervise(ctx, fn, opts)
+}
+
+// supervise implements [Supervise] in the calling goroutine.
+func supervise(ctx context.Context, fn func(context.Context), opts SupervisorOptions) {
+	logger := opts.Logger
+	if logger == nil {
+		logger = func(err error) {
+			logPrint(fmt.Errorf("runtimex: supervise: %w", err))
+		}
+	}
+	backoff := opts.InitialBackoff
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	maxBackoff := opts.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+	for restarts := 0; ctx.Err() == nil; restarts++ {
+		err := CatchPanic(func() {
+			fn(ctx)
+		})
+		if err == nil {
+			return
+		}
+		logger(err)
+		if opts.MaxRestarts > 0 && restarts >= opts.MaxRestarts {
+			logger(fmt.Errorf("giving up after %d restarts: %w", restarts, err))
+			return
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		backoff = min(2*backoff, maxBackoff)
+	}
+}
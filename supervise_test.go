@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupervise(t *testing.T) {
+	t.Run("runs fn in a background goroutine and restarts it", func(t *testing.T) {
+		done := make(chan struct{})
+		var calls int
+		Supervise(context.Background(), func(ctx context.Context) {
+			calls++
+			if calls <= 2 {
+				PanicOnError0(errors.New("transient error"))
+			}
+			close(done)
+		}, SupervisorOptions{
+			Logger:         func(err error) {},
+			InitialBackoff: time.Millisecond,
+		})
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("fn did not succeed")
+		}
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("logs giving up after max restarts", func(t *testing.T) {
+		logged := make(chan error, 16)
+		expectedErr := errors.New("persistent error")
+		Supervise(context.Background(), func(ctx context.Context) {
+			PanicOnError0(expectedErr)
+		}, SupervisorOptions{
+			Logger:         func(err error) { logged <- err },
+			MaxRestarts:    2,
+			InitialBackoff: time.Millisecond,
+		})
+		var errs []error
+		for len(errs) < 4 {
+			select {
+			case err := <-logged:
+				errs = append(errs, err)
+			case <-time.After(10 * time.Second):
+				t.Fatal("supervisor did not give up")
+			}
+		}
+		assert.EqualError(t, errs[0], "persistent error")
+		assert.EqualError(t, errs[3], "giving up after 2 restarts: persistent error")
+		assert.ErrorIs(t, errs[3], expectedErr)
+	})
+}
+
+// TestSuperviseLoop tests the loop implementing [Supervise] in the calling goroutine.
+func TestSuperviseLoop(t *testing.T) {
+	t.Run("with fn panicking a few times then succeeding returns", func(t *testing.T) {
+		var calls int
+		var logged []error
+		supervise(context.Background(), func(ctx context.Context) {
+			calls++
+			if calls <= 2 {
+				PanicOnError0(errors.New("transient error"))
+			}
+		}, SupervisorOptions{
+			Logger:         func(err error) { logged = append(logged, err) },
+			MaxRestarts:    5,
+			InitialBackoff: time.Millisecond,
+		})
+		assert.Equal(t, 3, calls)
+		if assert.Len(t, logged, 2) {
+			assert.EqualError(t, logged[0], "transient error")
+		}
+	})
+
+	t.Run("with fn always panicking gives up after max restarts", func(t *testing.T) {
+		var calls int
+		var logged []error
+		supervise(context.Background(), func(ctx context.Context) {
+			calls++
+			PanicOnError0(errors.New("persistent error"))
+		}, SupervisorOptions{
+			Logger:         func(err error) { logged = append(logged, err) },
+			MaxRestarts:    2,
+			InitialBackoff: time.Millisecond,
+		})
+		assert.Equal(t, 3, calls)
+		if assert.Len(t, logged, 4) {
+			assert.EqualError(t, logged[3], "giving up after 2 restarts: persistent error")
+		}
+	})
+
+	t.Run("with ctx canceled during the backoff returns", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var calls int
+		supervise(ctx, func(ctx context.Context) {
+			calls++
+			cancel()
+			panic("test value")
+		}, SupervisorOptions{
+			Logger:         func(err error) {},
+			InitialBackoff: time.Hour,
+		})
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("with ctx already canceled does not call fn", func(t *testing.T) {
+		var calls int
+		supervise(canceledContext(), func(ctx context.Context) {
+			calls++
+		}, SupervisorOptions{})
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("without a logger logs using log.Print", func(t *testing.T) {
+		originalLogPrint := logPrint
+		defer func() { logPrint = originalLogPrint }()
+		var printed []any
+		logPrint = func(v ...any) {
+			printed = append(printed, v...)
+		}
+		supervise(context.Background(), func(ctx context.Context) {
+			panic("test value")
+		}, SupervisorOptions{MaxRestarts: 1, InitialBackoff: time.Millisecond})
+		if assert.Len(t, printed, 3) {
+			assert.EqualError(t, printed[0].(error), "runtimex: supervise: panic: test value")
+			assert.EqualError(t, printed[2].(error),
+				"runtimex: supervise: giving up after 1 restarts: panic: test value")
+		}
+	})
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"fmt"
+	"time"
+)
+
+// AssertTimeInRange panics unless start <= t <= end. The value passed to
+// `panic()` is an [*AssertionError] whose message includes the times in
+// RFC3339 format, e.g., `time 2024-01-01T00:00:00Z not in range
+// [2024-02-01T00:00:00Z, 2024-03-01T00:00:00Z]`.
+func AssertTimeInRange(t, start, end time.Time) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if t.Before(start) || t.After(end) {
+		assertionFailed(fmt.Errorf("time %s not in range [%s, %s]",
+			formatTime(t), formatTime(start), formatTime(end)))
+	}
+}
+
+// AssertBefore panics unless t is strictly before limit. The value passed to
+// `panic()` is an [*AssertionError] whose message includes the times in
+// RFC3339 format, e.g., `time 2024-01-01T00:00:00Z not before
+// 2023-01-01T00:00:00Z`.
+func AssertBefore(t, limit time.Time) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !t.Before(limit) {
+		assertionFailed(fmt.Errorf("time %s not before %s", formatTime(t), formatTime(limit)))
+	}
+}
+
+// AssertAfter panics unless t is strictly after limit. The value passed to
+// `panic()` is an [*AssertionError] whose message includes the times in
+// RFC3339 format, e.g., `time 2023-01-01T00:00:00Z not after
+// 2024-01-01T00:00:00Z`.
+func AssertAfter(t, limit time.Time) {
+	if !assertionsCompiled || !assertionsEnabled() {
+		return
+	}
+	if !t.After(limit) {
+		assertionFailed(fmt.Errorf("time %s not after %s", formatTime(t), formatTime(limit)))
+	}
+}
+
+// formatTime formats t using RFC3339, including the fractional
+// seconds, if any, such that close times remain distinguishable.
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testDate returns midnight UTC of the given day of January 2024.
+func testDate(day int) time.Time {
+	return time.Date(2024, time.January, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAssertTimeInRange(t *testing.T) {
+	t.Run("with time in range does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertTimeInRange(testDate(15), testDate(10), testDate(20))
+		})
+	})
+
+	t.Run("with time on the boundaries does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertTimeInRange(testDate(10), testDate(10), testDate(20))
+			AssertTimeInRange(testDate(20), testDate(10), testDate(20))
+		})
+	})
+
+	t.Run("with time before start panics", func(t *testing.T) {
+		expected := "time 2024-01-09T00:00:00Z not in range [2024-01-10T00:00:00Z, 2024-01-20T00:00:00Z]"
+		assert.PanicsWithError(t, expected, func() {
+			AssertTimeInRange(testDate(9), testDate(10), testDate(20))
+		})
+	})
+
+	t.Run("with time after end panics", func(t *testing.T) {
+		expected := "time 2024-01-20T00:00:00.5Z not in range [2024-01-10T00:00:00Z, 2024-01-20T00:00:00Z]"
+		assert.PanicsWithError(t, expected, func() {
+			AssertTimeInRange(testDate(20).Add(500*time.Millisecond), testDate(10), testDate(20))
+		})
+	})
+}
+
+func TestAssertBefore(t *testing.T) {
+	t.Run("with time before limit does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertBefore(testDate(1), testDate(2))
+		})
+	})
+
+	t.Run("with time equal to limit panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "time 2024-01-02T00:00:00Z not before 2024-01-02T00:00:00Z", func() {
+			AssertBefore(testDate(2), testDate(2))
+		})
+	})
+
+	t.Run("with time after limit panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "time 2024-01-03T00:00:00Z not before 2024-01-02T00:00:00Z", func() {
+			AssertBefore(testDate(3), testDate(2))
+		})
+	})
+}
+
+func TestAssertAfter(t *testing.T) {
+	t.Run("with time after limit does not panic", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			AssertAfter(testDate(2), testDate(1))
+		})
+	})
+
+	t.Run("with time equal to limit panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "time 2024-01-02T00:00:00Z not after 2024-01-02T00:00:00Z", func() {
+			AssertAfter(testDate(2), testDate(2))
+		})
+	})
+
+	t.Run("with time before limit panics", func(t *testing.T) {
+		assert.PanicsWithError(t, "time 2024-01-01T00:00:00Z not after 2024-01-02T00:00:00Z", func() {
+			AssertAfter(testDate(1), testDate(2))
+		})
+	})
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+// TryScope accumulates errors from several fallible calls such that you can
+// panic once with all of them using [*TryScope.Check], rather than panicking
+// on the first error like [PanicOnError0] does. For example:
+//
+//	func validate(cfg *Config) {
+//		var scope runtimex.TryScope
+//		scope.Do(checkName(cfg.Name))
+//		port := runtimex.Do1(&scope, parsePort(cfg.Port))
+//		scope.Do(checkPort(port))
+//		scope.Check()
+//	}
+//
+// The zero value is ready to use. A TryScope is not goroutine safe. It uses
+// an [ErrorCollector] to accumulate the errors.
+type TryScope struct {
+	ec ErrorCollector
+}
+
+// Do records err, if not nil, like [*ErrorCollector.Add].
+func (s *TryScope) Do(err error) {
+	s.ec.Add(err)
+}
+
+// Check panics like [PanicOnError0] with the recorded errors joined using
+// [errors.Join]. If there are no recorded errors, it returns normally.
+func (s *TryScope) Check() {
+	panicOnError(1, s.ec.join())
+}
+
+// Do1 is like [*TryScope.Do] but for functions returning a value and an error.
+// It records err in s, if not nil, and returns v even on error, such that the
+// code can continue and report further errors. Do1 is a function rather than
+// a method because Go does not support generic methods.
+func Do1[T any](s *TryScope, v T, err error) T {
+	s.Do(err)
+	return v
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package runtimex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTryScope(t *testing.T) {
+	t.Run("with zero errors", func(t *testing.T) {
+		var scope TryScope
+		scope.Do(nil)
+		assert.Equal(t, 17, Do1(&scope, 17, nil))
+		assert.NotPanics(t, func() {
+			scope.Check()
+		})
+	})
+
+	t.Run("with one error", func(t *testing.T) {
+		var scope TryScope
+		expectedErr := errors.New("first")
+		scope.Do(nil)
+		scope.Do(expectedErr)
+		err := recoverError(func() {
+			scope.Check()
+		})
+		assert.ErrorIs(t, err, expectedErr)
+		assert.EqualError(t, err, "first")
+	})
+
+	t.Run("with several errors", func(t *testing.T) {
+		var scope TryScope
+		err1 := errors.New("first")
+		err2 := errors.New("second")
+		err3 := errors.New("third")
+		scope.Do(err1)
+		assert.Equal(t, 17, Do1(&scope, 17, err2))
+		assert.Equal(t, "", Do1(&scope, "", nil))
+		scope.Do(err3)
+		err := recoverError(func() {
+			scope.Check()
+		})
+		assert.ErrorIs(t, err, err1)
+		assert.ErrorIs(t, err, err2)
+		assert.ErrorIs(t, err, err3)
+		assert.EqualError(t, err, "first\nsecond\nthird")
+	})
+}